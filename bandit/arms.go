@@ -0,0 +1,90 @@
+package bandit
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+
+	"github.com/rlouf/gmc/conjugate"
+)
+
+// An Arm maintains the posterior distribution of the expected reward of one
+// of the bandit's arms.
+type Arm interface {
+	Update(reward float64) // Update conditions the posterior on an observed reward
+	Sample() float64       // Sample draws an expected reward from the posterior
+	Mean() float64         // Mean returns the posterior mean of the expected reward
+}
+
+// A BetaBernoulli arm yields rewards that are either 0 or 1. The probability
+// of success has a Beta posterior distribution.
+type BetaBernoulli struct {
+	*conjugate.BetaBernoulli
+
+	Src *rand.Rand
+}
+
+// NewBetaBernoulli returns an arm with a uniform prior on the probability
+// of success.
+func NewBetaBernoulli(src *rand.Rand) *BetaBernoulli {
+	return &BetaBernoulli{
+		BetaBernoulli: conjugate.NewBetaBernoulli(1, 1),
+		Src:           src,
+	}
+}
+
+// Update counts a reward above 0.5 as a success and any other reward as a
+// failure.
+func (b *BetaBernoulli) Update(reward float64) {
+	if reward > 0.5 {
+		b.BetaBernoulli.Update(1)
+	} else {
+		b.BetaBernoulli.Update(0)
+	}
+}
+
+// Sample draws a probability of success from the posterior.
+func (b *BetaBernoulli) Sample() float64 {
+	alpha, beta := b.Params()
+	dist := distuv.Beta{Alpha: alpha, Beta: beta, Src: b.Src}
+	return dist.Rand()
+}
+
+// A Normal arm yields normally distributed rewards with a known standard
+// deviation Noise. The expected reward has a Normal(Mu, Sigma) posterior
+// distribution.
+type Normal struct {
+	Mu    float64
+	Sigma float64
+	Noise float64
+
+	Src *rand.Rand
+}
+
+// NewNormal returns an arm with a Normal(mu, sigma) prior on the expected
+// reward and rewards of standard deviation noise.
+func NewNormal(mu, sigma, noise float64, src *rand.Rand) *Normal {
+	return &Normal{Mu: mu, Sigma: sigma, Noise: noise, Src: src}
+}
+
+// Update conditions the posterior of the expected reward on a reward, which
+// shrinks Sigma.
+func (n *Normal) Update(reward float64) {
+	priorPrecision := 1 / (n.Sigma * n.Sigma)
+	noisePrecision := 1 / (n.Noise * n.Noise)
+	precision := priorPrecision + noisePrecision
+	n.Mu = (priorPrecision*n.Mu + noisePrecision*reward) / precision
+	n.Sigma = math.Sqrt(1 / precision)
+}
+
+// Sample draws an expected reward from the posterior.
+func (n *Normal) Sample() float64 {
+	dist := distuv.Normal{Mu: n.Mu, Sigma: n.Sigma, Src: n.Src}
+	return dist.Rand()
+}
+
+// Mean returns the posterior mean of the expected reward, Mu.
+func (n *Normal) Mean() float64 {
+	return n.Mu
+}
@@ -0,0 +1,53 @@
+// Package bandit implements multi-armed bandits that choose arms by Thompson
+// sampling from the posterior distribution of each arm's expected reward.
+package bandit
+
+import (
+	"log"
+	"sync"
+)
+
+// A Bandit selects arms and updates their posterior distribution as
+// rewards are observed. It is safe for concurrent use.
+type Bandit struct {
+	Arms []Arm
+
+	mu sync.Mutex
+}
+
+// New returns a bandit that chooses among the arms, in this order. It
+// panics if no arm is given.
+func New(arms ...Arm) *Bandit {
+	if len(arms) == 0 {
+		log.Panicf("a bandit needs at least one arm")
+	}
+	return &Bandit{Arms: arms}
+}
+
+// SelectArm returns the index of the arm to play using Thompson sampling: it
+// draws an expected reward from each arm's posterior and picks the highest.
+// Each arm is thus played with the posterior probability that it is the
+// best one.
+func (b *Bandit) SelectArm() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	best, bestReward := 0, b.Arms[0].Sample()
+	for i := 1; i < len(b.Arms); i++ {
+		if reward := b.Arms[i].Sample(); reward > bestReward {
+			best, bestReward = i, reward
+		}
+	}
+	return best
+}
+
+// Update records the reward obtained by playing an arm.
+func (b *Bandit) Update(arm int, reward float64) {
+	if arm < 0 || arm >= len(b.Arms) {
+		log.Panicf("the bandit has %d arms, got arm %d", len(b.Arms), arm)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.Arms[arm].Update(reward)
+}
@@ -0,0 +1,96 @@
+// Package bench compares samplers empirically by running them on the same
+// target with the same budget.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/rlouf/gmc/diagnostics"
+)
+
+// A Case is a configured sampler. Run must draw an independent chain of
+// numSamples samples each time it is called, and return them as the rows of
+// a matrix with one column per variable.
+type Case struct {
+	Name string
+	Run  func(numSamples int) *mat.Dense
+}
+
+// A Result contains the efficiency of a sampler on the target. The values
+// computed per variable are indexed by the column of the variable.
+type Result struct {
+	Name         string
+	Duration     time.Duration // total wall-clock time over all the chains
+	ESSPerSecond []float64     // pooled effective samples per second
+	RHat         []float64
+}
+
+// MinESSPerSecond returns the efficiency of the sampler on the variable on
+// which it performs worst.
+func (r Result) MinESSPerSecond() float64 {
+	min := math.Inf(1)
+	for _, e := range r.ESSPerSecond {
+		min = math.Min(min, e)
+	}
+	return min
+}
+
+// MaxRHat returns the largest R-hat across variables.
+func (r Result) MaxRHat() float64 {
+	max := math.Inf(-1)
+	for _, rhat := range r.RHat {
+		max = math.Max(max, rhat)
+	}
+	return max
+}
+
+// Run runs numChains chains of numSamples samples for each case and
+// measures the effective sample size per second and R-hat of each
+// variable.
+func Run(cases []Case, numSamples, numChains int) []Result {
+	var results []Result
+	for _, c := range cases {
+		start := time.Now()
+		chains := make([]*mat.Dense, numChains)
+		for i := range chains {
+			chains[i] = c.Run(numSamples)
+		}
+		duration := time.Since(start)
+
+		_, numVariables := chains[0].Dims()
+		result := Result{
+			Name:         c.Name,
+			Duration:     duration,
+			ESSPerSecond: make([]float64, numVariables),
+			RHat:         make([]float64, numVariables),
+		}
+		for j := 0; j < numVariables; j++ {
+			columns := make([][]float64, numChains)
+			var ess float64
+			for i, chain := range chains {
+				columns[i] = mat.Col(nil, j, chain)
+				ess += diagnostics.EffectiveSampleSize(columns[i])
+			}
+			result.ESSPerSecond[j] = ess / duration.Seconds()
+			result.RHat[j] = diagnostics.RHat(columns)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Table writes the results as a table, one sampler per line.
+func Table(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "sampler\ttime\tmin ESS/s\tmax R-hat\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%v\t%.1f\t%.3f\t\n", r.Name, r.Duration.Round(time.Millisecond), r.MinESSPerSecond(), r.MaxRHat())
+	}
+	return tw.Flush()
+}
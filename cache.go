@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/samplemv"
+
+	"github.com/rlouf/gmc/node"
+	"github.com/rlouf/gmc/trace"
+)
+
+// CacheOptions configures where SampleCached stores the traces, and whether
+// it should ignore a trace that is already stored.
+type CacheOptions struct {
+	Dir        string
+	ForceRefit bool
+}
+
+// SampleCached behaves like Sample but stores the trace on disk, addressed
+// by a hash of the model's specification, of its observed values and of the
+// sampler's configuration, including its proposal distribution. If a trace
+// with the same hash is found it is returned instead of sampling again,
+// unless `ForceRefit` is set.
+//
+// This is useful in analysis scripts that are run over and over again while
+// only the last steps change.
+func (m *Model) SampleCached(opts CacheOptions, nSamples int, initial []float64, sampler samplemv.MetropolisHastingser) (trace.Trace, error) {
+	key := fmt.Sprintf("%s\nsamples=%d initial=%v burnin=%d rate=%d proposal=%s", m.spec(), nSamples, initial, sampler.BurnIn, sampler.Rate, describeProposal(sampler.Proposal))
+	hash := sha256.Sum256([]byte(key))
+	path := filepath.Join(opts.Dir, hex.EncodeToString(hash[:])+".json")
+
+	if !opts.ForceRefit {
+		content, err := ioutil.ReadFile(path)
+		if err == nil {
+			var cached trace.Trace
+			if err := json.Unmarshal(content, &cached); err != nil {
+				return nil, fmt.Errorf("could not read the cached trace %s: %v", path, err)
+			}
+			return cached, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	samples := m.Sample(nSamples, initial, sampler)
+
+	content, err := json.Marshal(samples)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// spec returns a description of the model's graph that only depends on its
+// structure, its constants and its observed values. Random variables are
+// identified by their name and deterministic nodes by their position.
+func (m *Model) spec() string {
+	ids := make(map[node.Var]string)
+	for i, d := range m.deterministic {
+		ids[d] = fmt.Sprintf("#%d", i)
+	}
+	for _, v := range m.stochastic {
+		ids[v] = v.Name()
+	}
+	for _, o := range m.observed {
+		ids[o] = o.Name()
+	}
+
+	var b bytes.Buffer
+	for i, d := range m.deterministic {
+		fmt.Fprintf(&b, "#%d = %s\n", i, describe(d, ids))
+	}
+	for _, v := range m.stochastic {
+		fmt.Fprintf(&b, "%s ~ %s\n", v.Name(), describe(v, ids))
+	}
+	for _, o := range m.observed {
+		fmt.Fprintf(&b, "%s ~ %s observed=%v power=%v\n", o.Name(), describe(o, ids), o.Value(), m.power(o))
+	}
+	for _, g := range m.generated {
+		fmt.Fprintf(&b, "%s = %s\n", g.name, describe(g.variable, ids))
+	}
+	return b.String()
+}
+
+// describe lists the type of a node and the value of its exported fields,
+// replacing the nodes of the model it depends on by their identifier. Nodes
+// that are not part of the model, such as the distribution wrapped by
+// node.Truncated or the components of node.Mixture, are described in full.
+//
+// describe panics on fields it does not know how to describe: a parameter
+// left out of the description would give different models the same hash.
+func describe(v node.Var, ids map[node.Var]string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%T", v)
+	if c, ok := v.(*node.Constant); ok {
+		fmt.Fprintf(&b, "(%v)", c.Value())
+		return b.String()
+	}
+
+	// A node that refers back to itself, e.g. through the vector it is a
+	// component of, is only described once.
+	if _, ok := ids[v]; !ok {
+		ids[v] = fmt.Sprintf("%T(...)", v)
+		defer delete(ids, v)
+	}
+	describeFields(&b, reflect.ValueOf(v), ids)
+	return b.String()
+}
+
+// describeFields writes the exported fields of the struct value points to,
+// except for random sources.
+func describeFields(b *bytes.Buffer, value reflect.Value, ids map[node.Var]string) {
+	value = reflect.Indirect(value)
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" || field.Type == randType {
+			continue
+		}
+		fmt.Fprintf(b, " %s=%s", field.Name, describeValue(value.Field(i), ids))
+	}
+}
+
+var (
+	randType   = reflect.TypeOf((*rand.Rand)(nil))
+	sourceType = reflect.TypeOf((*rand.Source)(nil)).Elem()
+)
+
+func describeValue(value reflect.Value, ids map[node.Var]string) string {
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Slice:
+		if value.IsNil() {
+			return "nil"
+		}
+	}
+	if v, ok := value.Interface().(node.Var); ok {
+		if id, ok := ids[v]; ok {
+			return id
+		}
+		return "(" + describe(v, ids) + ")"
+	}
+	if matrix, ok := value.Interface().(*mat.SymDense); ok {
+		return fmt.Sprintf("%v", mat.Formatted(matrix, mat.FormatMATLAB()))
+	}
+
+	switch value.Kind() {
+	case reflect.Float64, reflect.Int, reflect.Bool:
+		return fmt.Sprint(value.Interface())
+	case reflect.Slice:
+		elements := make([]string, value.Len())
+		for i := range elements {
+			elements[i] = describeValue(value.Index(i), ids)
+		}
+		return "[" + strings.Join(elements, " ") + "]"
+	case reflect.Ptr:
+		if value.Elem().Kind() == reflect.Struct {
+			var b bytes.Buffer
+			fmt.Fprintf(&b, "%s{", value.Type())
+			describeFields(&b, value, ids)
+			b.WriteString(" }")
+			return b.String()
+		}
+	}
+	log.Panicf("cannot describe a value of type %s in the specification of the model", value.Type())
+	return ""
+}
+
+// describeProposal serializes the proposal distribution of a sampler, e.g.
+// the covariance of samplemv.ProposalNormal, which it keeps in unexported
+// fields. Random sources are skipped since their state changes as they are
+// used.
+func describeProposal(proposal samplemv.MHProposal) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%T", proposal)
+	dump(&b, reflect.ValueOf(proposal))
+	return b.String()
+}
+
+func dump(b *bytes.Buffer, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		if value.Type().Implements(sourceType) || value.Elem().Type().Implements(sourceType) {
+			b.WriteString("src")
+			return
+		}
+		dump(b, value.Elem())
+	case reflect.Struct:
+		b.WriteString("{")
+		for i := 0; i < value.NumField(); i++ {
+			dump(b, value.Field(i))
+			b.WriteString(" ")
+		}
+		b.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		b.WriteString("[")
+		for i := 0; i < value.Len(); i++ {
+			dump(b, value.Index(i))
+			b.WriteString(" ")
+		}
+		b.WriteString("]")
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(strconv.FormatFloat(value.Float(), 'g', -1, 64))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(value.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b.WriteString(strconv.FormatUint(value.Uint(), 10))
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(value.Bool()))
+	case reflect.String:
+		b.WriteString(strconv.Quote(value.String()))
+	default:
+		log.Panicf("cannot describe a value of type %s in the proposal distribution", value.Type())
+	}
+}
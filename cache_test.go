@@ -0,0 +1,151 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/rlouf/gmc/node"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/samplemv"
+)
+
+// TestSpecParameters checks that models that differ only in a parameter that
+// is not a scalar variable have different specifications.
+func TestSpecParameters(t *testing.T) {
+	builds := map[string]func(x float64) *Model{
+		"categorical": func(x float64) *Model {
+			m := NewModel()
+			m.Categorical("k", []node.Var{m.Constant(x), m.Constant(1 - x)})
+			return m
+		},
+		"normal mixture": func(x float64) *Model {
+			m := NewModel()
+			w := []node.Var{m.Constant(0.5), m.Constant(0.5)}
+			m.NormalMixture("y", w, []node.Var{m.Constant(0), m.Constant(x)}, []node.Var{m.Constant(1), m.Constant(1)})
+			return m
+		},
+		"mixture": func(x float64) *Model {
+			m := NewModel()
+			m.Mixture("y", []node.Var{m.Constant(0.5), m.Constant(0.5)},
+				node.NewNormal("y_0", node.NewConstant(0), node.NewConstant(1), m.Src),
+				node.NewNormal("y_1", node.NewConstant(x), node.NewConstant(1), m.Src))
+			return m
+		},
+		"wishart": func(x float64) *Model {
+			m := NewModel()
+			m.Wishart("W", mat.NewSymDense(2, []float64{1, 0, 0, x}), 3)
+			return m
+		},
+		"inverse wishart": func(x float64) *Model {
+			m := NewModel()
+			scale := mat.NewSymDense(2, []float64{1, 0, 0, 1})
+			if x == 1 {
+				m.Wishart("W", scale, 3)
+			} else {
+				m.InverseWishart("W", scale, 3)
+			}
+			return m
+		},
+	}
+	for name, build := range builds {
+		if build(1).spec() == build(2).spec() {
+			t.Errorf("%s: models with different parameters have the same specification", name)
+		}
+		if build(1).spec() != build(1).spec() {
+			t.Errorf("%s: the specification of a model is not reproducible", name)
+		}
+	}
+}
+
+type unknownField struct {
+	node.Normal
+	Callback func()
+}
+
+func TestSpecUnknownField(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("describing a field of unknown type did not panic")
+		}
+	}()
+	m := NewModel()
+	m.stochastic = append(m.stochastic, &unknownField{Normal: *node.NewNormal("x", node.NewConstant(0), node.NewConstant(1), nil)})
+	m.spec()
+}
+
+func TestSampleCachedProposal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gmc-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := NewModel()
+	m.Normal("x", m.Constant(0), m.Constant(1))
+	sample := func(variance float64) []float64 {
+		s := NewMetropolisHastingsSampler(m)
+		s.BurnIn = 0
+		proposal, _ := samplemv.NewProposalNormal(mat.NewSymDense(1, []float64{variance}), m.Src)
+		s.Proposal = proposal
+		samples, err := m.SampleCached(CacheOptions{Dir: dir}, 10, []float64{0}, *s.MetropolisHastingser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return samples["x"]
+	}
+
+	first := sample(0.05)
+	if cached := sample(0.05); !equal(first, cached) {
+		t.Error("the same model and sampler did not hit the cache")
+	}
+	if other := sample(5); equal(first, other) {
+		t.Error("a different proposal returned the cached trace")
+	}
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSpecTruncated(t *testing.T) {
+	build := func(mu float64) *Model {
+		m := NewModel()
+		m.Truncated(node.NewNormal("x", node.NewConstant(mu), node.NewConstant(1), m.Src), 0, 10)
+		return m
+	}
+	if build(0).spec() == build(5).spec() {
+		t.Error("truncated models that differ in the wrapped distribution have the same specification")
+	}
+}
+
+func TestSpecCensored(t *testing.T) {
+	build := func(rate float64, interval bool) *Model {
+		m := NewModel()
+		dist := node.NewExponential("t", node.NewConstant(rate), m.Src)
+		var c *node.Censored
+		if interval {
+			c = m.IntervalCensored(dist, 0, 2)
+		} else {
+			c = m.Censored(dist, 0, 2)
+		}
+		if err := m.Observe(c, 1); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	if build(1, false).spec() == build(2, false).spec() {
+		t.Error("censored models that differ in the wrapped distribution have the same specification")
+	}
+	if build(1, false).spec() == build(1, true).spec() {
+		t.Error("censored and interval-censored models have the same specification")
+	}
+}
@@ -0,0 +1,104 @@
+// Package cluster provides tools to summarize the posterior distribution of
+// partitions, as sampled in mixture models where each observation has a
+// latent assignment variable.
+package cluster
+
+import (
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Similarity returns the posterior similarity matrix of the observations
+// whose assignment variables are listed in `names`. The (i, j) entry is the
+// proportion of draws in which observations i and j belong to the same
+// cluster.
+func Similarity(trace map[string][]float64, names []string) *mat.SymDense {
+	assignments := extract(trace, names)
+	n := len(names)
+	numDraws := len(assignments[0])
+
+	similarity := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		similarity.SetSym(i, i, 1)
+		for j := i + 1; j < n; j++ {
+			together := 0
+			for s := 0; s < numDraws; s++ {
+				if assignments[i][s] == assignments[j][s] {
+					together++
+				}
+			}
+			similarity.SetSym(i, j, float64(together)/float64(numDraws))
+		}
+	}
+
+	return similarity
+}
+
+// MinBinder returns the partition, among the sampled ones, that minimizes
+// the posterior expected Binder loss with equal misclassification costs, and
+// the value of this loss.
+//
+// Clusters in the returned partition are labelled 0, 1, ... in order of
+// first appearance.
+func MinBinder(trace map[string][]float64, names []string) ([]int, float64) {
+	assignments := extract(trace, names)
+	similarity := Similarity(trace, names)
+	n := len(names)
+	numDraws := len(assignments[0])
+
+	best, bestLoss := 0, math.Inf(1)
+	for s := 0; s < numDraws; s++ {
+		var loss float64
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				together := 0.0
+				if assignments[i][s] == assignments[j][s] {
+					together = 1
+				}
+				loss += math.Abs(together - similarity.At(i, j))
+			}
+		}
+		if loss < bestLoss {
+			best, bestLoss = s, loss
+		}
+	}
+
+	partition := make([]int, n)
+	labels := make(map[int]int)
+	for i := 0; i < n; i++ {
+		raw := assignments[i][best]
+		if _, ok := labels[raw]; !ok {
+			labels[raw] = len(labels)
+		}
+		partition[i] = labels[raw]
+	}
+
+	return partition, bestLoss
+}
+
+// extract returns the assignments of each observation, checking that they
+// were sampled the same number of times. The samples are rounded to the
+// nearest label: the trace holds the values proposed by the sampler, which
+// are not integers even for discrete variables.
+func extract(trace map[string][]float64, names []string) [][]int {
+	if len(names) == 0 {
+		log.Panicf("needed at least one assignment variable")
+	}
+	assignments := make([][]int, len(names))
+	for i, name := range names {
+		samples, ok := trace[name]
+		if !ok {
+			log.Panicf("The trace is missing variable %s", name)
+		}
+		if len(samples) == 0 || (i > 0 && len(samples) != len(assignments[0])) {
+			log.Panicf("variable %s has %d samples, expected %d", name, len(samples), len(assignments[0]))
+		}
+		assignments[i] = make([]int, len(samples))
+		for s, x := range samples {
+			assignments[i][s] = int(math.Round(x))
+		}
+	}
+	return assignments
+}
@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSimilarityUnrounded checks that draws of the assignment variables are
+// compared as labels, whatever their distance to the integer they round to.
+func TestSimilarityUnrounded(t *testing.T) {
+	trace := map[string][]float64{
+		"z_0": {0.1, 1.2, 0.9, 2.3},
+		"z_1": {-0.2, 0.8, 1.3, 1.6},
+		"z_2": {1.1, 0.7, 2.2, 2.4},
+	}
+	names := []string{"z_0", "z_1", "z_2"}
+
+	similarity := Similarity(trace, names)
+	// The labels are (0, 1, 1, 2), (0, 1, 1, 2) and (1, 1, 2, 2).
+	want := [][]float64{{1, 1, 0.5}, {1, 1, 0.5}, {0.5, 0.5, 1}}
+	for i := range want {
+		for j := range want[i] {
+			if got := similarity.At(i, j); math.Abs(got-want[i][j]) > 1e-12 {
+				t.Errorf("similarity of %d and %d is %f, want %f", i, j, got, want[i][j])
+			}
+		}
+	}
+
+	partition, _ := MinBinder(trace, names)
+	if partition[0] != partition[1] || partition[0] == partition[2] {
+		t.Errorf("got the partition %v, want z_0 and z_1 together and z_2 apart", partition)
+	}
+}
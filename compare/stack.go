@@ -0,0 +1,190 @@
+// Package compare weighs and combines the predictions of several models
+// fitted to the same data.
+//
+// The functions of the package take the pointwise log-likelihood of each
+// model: a matrix with one row per posterior sample and one column per
+// observation, as returned by Model.PointwiseLogLik.
+package compare
+
+import (
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+)
+
+// LOO estimates the leave-one-out log predictive density of each
+// observation, log p(y_i | y_-i), from the samples of the full posterior.
+//
+// The density is estimated by importance sampling with weights
+// 1 / p(y_i | theta_s), truncated at sqrt(S) times their mean to bound their
+// variance:
+//
+// "Truncated Importance Sampling" (Ionides 2008)
+// https://doi.org/10.1198/106186008X320456
+func LOO(logLik [][]float64) []float64 {
+	numSamples := len(logLik)
+	if numSamples == 0 {
+		log.Panicf("the log-likelihood has no samples")
+	}
+	numObs := len(logLik[0])
+
+	loo := make([]float64, numObs)
+	logWeights := make([]float64, numSamples)
+	terms := make([]float64, numSamples)
+	for i := range loo {
+		for s, row := range logLik {
+			if len(row) != numObs {
+				log.Panicf("sample %d has %d observations, expected %d", s, len(row), numObs)
+			}
+			logWeights[s] = -row[i]
+		}
+		logMean := floats.LogSumExp(logWeights) - math.Log(float64(numSamples))
+		logMax := logMean + 0.5*math.Log(float64(numSamples))
+		for s, lw := range logWeights {
+			logWeights[s] = math.Min(lw, logMax)
+			terms[s] = logWeights[s] + logLik[s][i]
+		}
+		loo[i] = floats.LogSumExp(terms) - floats.LogSumExp(logWeights)
+	}
+
+	return loo
+}
+
+// Stack returns the weights of the linear combination of the models'
+// predictive distributions that maximizes the leave-one-out log score:
+//
+// max_w sum_i log(sum_k w_k p(y_i | y_-i, model k))
+//
+// Unlike weights based on the marginal likelihood, stacking weights do not
+// assume that one of the models is true:
+//
+// "Using stacking to average Bayesian predictive distributions" (Yao et al. 2018)
+// https://doi.org/10.1214/17-BA1091
+//
+// The weights are found with the fixed-point iterations of the EM algorithm
+// for the weights of a mixture, which increase the objective at every step.
+func Stack(logLiks [][][]float64) []float64 {
+	densities := looDensities(logLiks)
+	numModels, numObs := len(densities), len(densities[0])
+
+	weights := make([]float64, numModels)
+	for k := range weights {
+		weights[k] = 1 / float64(numModels)
+	}
+
+	const (
+		maxIterations = 10000
+		tolerance     = 1e-10
+	)
+	updated := make([]float64, numModels)
+	for iter := 0; iter < maxIterations; iter++ {
+		for k := range updated {
+			updated[k] = 0
+		}
+		for i := 0; i < numObs; i++ {
+			var mixture float64
+			for k := range weights {
+				mixture += weights[k] * densities[k][i]
+			}
+			for k := range weights {
+				updated[k] += weights[k] * densities[k][i] / mixture / float64(numObs)
+			}
+		}
+		converged := floats.Distance(weights, updated, math.Inf(1)) < tolerance
+		copy(weights, updated)
+		if converged {
+			break
+		}
+	}
+
+	return weights
+}
+
+// PseudoBMA returns weights proportional to the exponential of each model's
+// expected log predictive density estimated by LOO, a cheaper alternative to
+// stacking that tends to put all the weight on a single model.
+func PseudoBMA(logLiks [][][]float64) []float64 {
+	elpd := make([]float64, len(logLiks))
+	for k, logLik := range logLiks {
+		elpd[k] = floats.Sum(LOO(logLik))
+	}
+	logNorm := floats.LogSumExp(elpd)
+	weights := make([]float64, len(elpd))
+	for k := range weights {
+		weights[k] = math.Exp(elpd[k] - logNorm)
+	}
+	return weights
+}
+
+// looDensities returns the leave-one-out predictive density of each
+// observation under each model. The densities of an observation are scaled
+// by a common factor, which does not change the stacking weights, to avoid
+// underflows.
+func looDensities(logLiks [][][]float64) [][]float64 {
+	if len(logLiks) < 2 {
+		log.Panicf("needed at least 2 models, got %d", len(logLiks))
+	}
+	loos := make([][]float64, len(logLiks))
+	for k, logLik := range logLiks {
+		loos[k] = LOO(logLik)
+		if len(loos[k]) != len(loos[0]) {
+			log.Panicf("model %d has %d observations, expected %d", k, len(loos[k]), len(loos[0]))
+		}
+	}
+
+	densities := make([][]float64, len(loos))
+	for k := range densities {
+		densities[k] = make([]float64, len(loos[0]))
+	}
+	for i := range loos[0] {
+		max := math.Inf(-1)
+		for k := range loos {
+			max = math.Max(max, loos[k][i])
+		}
+		for k := range loos {
+			densities[k][i] = math.Exp(loos[k][i] - max)
+		}
+	}
+	return densities
+}
+
+// Mix draws numSamples samples from the mixture of the models' predictive
+// distributions with the given weights. Each sample of the mixture is a
+// sample of one of the models, chosen with probability equal to its weight.
+// The predictions of every model must contain the same variables.
+func Mix(predictions []map[string][]float64, weights []float64, numSamples int, src *rand.Rand) map[string][]float64 {
+	if len(predictions) != len(weights) {
+		log.Panicf("needed %d weights, got %d", len(predictions), len(weights))
+	}
+	cumulative := make([]float64, len(weights))
+	floats.CumSum(cumulative, weights)
+	total := cumulative[len(cumulative)-1]
+
+	mixed := make(map[string][]float64)
+	for name := range predictions[0] {
+		mixed[name] = make([]float64, numSamples)
+	}
+	for n := 0; n < numSamples; n++ {
+		u := src.Float64() * total
+		k := 0
+		for k < len(cumulative)-1 && u >= cumulative[k] {
+			k++
+		}
+		loc := -1
+		for name := range mixed {
+			samples, ok := predictions[k][name]
+			if !ok || len(samples) == 0 {
+				log.Panicf("the predictions of model %d are missing variable %s", k, name)
+			}
+			// The variables of a sample must come from the same draw.
+			if loc == -1 {
+				loc = src.Intn(len(samples))
+			}
+			mixed[name][n] = samples[loc]
+		}
+	}
+
+	return mixed
+}
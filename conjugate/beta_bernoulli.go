@@ -0,0 +1,66 @@
+// Package conjugate implements closed-form online updates of posterior
+// distributions for conjugate prior-likelihood pairs. They are meant for
+// services that need to update beliefs at a high rate and cannot afford to
+// run a sampler; their parameterizations match the ones of the nodes so the
+// results can be compared with a full model.
+//
+// All the updaters are safe for concurrent use.
+package conjugate
+
+import (
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/rlouf/gmc/node"
+)
+
+// BetaBernoulli maintains the Beta(alpha, beta) posterior distribution of
+// the probability of success of Bernoulli trials.
+type BetaBernoulli struct {
+	alpha float64
+	beta  float64
+
+	mu sync.RWMutex
+}
+
+// NewBetaBernoulli returns an updater with a Beta(alpha, beta) prior on the
+// probability of success, e.g. Beta(1, 1) for a uniform prior.
+func NewBetaBernoulli(alpha, beta float64) *BetaBernoulli {
+	return &BetaBernoulli{alpha: alpha, beta: beta}
+}
+
+// Update conditions the posterior on the outcome of a trial, 1 for a success
+// and 0 for a failure.
+func (b *BetaBernoulli) Update(x float64) {
+	b.UpdateCounts(x, 1-x)
+}
+
+// UpdateCounts conditions the posterior on a batch of trials.
+func (b *BetaBernoulli) UpdateCounts(successes, failures float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.alpha += successes
+	b.beta += failures
+}
+
+// Params returns the parameters of the posterior Beta distribution.
+func (b *BetaBernoulli) Params() (alpha, beta float64) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.alpha, b.beta
+}
+
+// Mean returns the posterior mean of the probability of success, which is
+// also the posterior predictive probability of a success.
+func (b *BetaBernoulli) Mean() float64 {
+	alpha, beta := b.Params()
+	return alpha / (alpha + beta)
+}
+
+// Node returns a Beta node with the current posterior as distribution, so
+// it can be used as a prior in a model.
+func (b *BetaBernoulli) Node(name string, src *rand.Rand) *node.Beta {
+	alpha, beta := b.Params()
+	return node.NewBeta(name, node.NewConstant(alpha), node.NewConstant(beta), src)
+}
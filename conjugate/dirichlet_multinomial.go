@@ -0,0 +1,69 @@
+package conjugate
+
+import (
+	"log"
+	"sync"
+)
+
+// DirichletMultinomial maintains the Dirichlet posterior distribution of the
+// probabilities of the categories of categorical observations.
+type DirichletMultinomial struct {
+	alphas []float64
+
+	mu sync.RWMutex
+}
+
+// NewDirichletMultinomial returns an updater with a Dirichlet(alphas) prior
+// on the probabilities of the categories, one per concentration parameter.
+// The slice is copied.
+func NewDirichletMultinomial(alphas []float64) *DirichletMultinomial {
+	if len(alphas) < 2 {
+		log.Panicf("needed at least 2 categories, got %d", len(alphas))
+	}
+	return &DirichletMultinomial{alphas: append([]float64(nil), alphas...)}
+}
+
+// Update conditions the posterior on an observation of the category with
+// index `category`.
+func (d *DirichletMultinomial) Update(category int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if category < 0 || category >= len(d.alphas) {
+		log.Panicf("the category must be between 0 and %d, got %d", len(d.alphas)-1, category)
+	}
+	d.alphas[category]++
+}
+
+// UpdateCounts conditions the posterior on the number of observations of
+// each category.
+func (d *DirichletMultinomial) UpdateCounts(counts []float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(counts) != len(d.alphas) {
+		log.Panicf("needed %d counts, got %d", len(d.alphas), len(counts))
+	}
+	for i, c := range counts {
+		d.alphas[i] += c
+	}
+}
+
+// Params returns the concentration parameters of the posterior distribution.
+func (d *DirichletMultinomial) Params() []float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]float64(nil), d.alphas...)
+}
+
+// Mean returns the posterior mean of the categories' probabilities, which is
+// also the posterior predictive distribution of the next observation.
+func (d *DirichletMultinomial) Mean() []float64 {
+	alphas := d.Params()
+	var total float64
+	for _, a := range alphas {
+		total += a
+	}
+	for i := range alphas {
+		alphas[i] /= total
+	}
+	return alphas
+}
@@ -0,0 +1,64 @@
+package conjugate
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// NormalGamma maintains the posterior distribution of the mean and
+// precision of normally distributed observations. The precision tau follows
+// a Gamma(Alpha, Beta) distribution (shape/rate), and conditionally on tau
+// the mean follows a Normal(Mu, 1/sqrt(Lambda*tau)).
+type NormalGamma struct {
+	mu     float64
+	lambda float64
+	alpha  float64
+	beta   float64
+
+	lock sync.RWMutex
+}
+
+// NewNormalGamma returns an updater whose prior is the NormalGamma(mu,
+// lambda, alpha, beta) distribution. The prior is worth lambda observations
+// of the mean and 2 alpha observations of the precision.
+func NewNormalGamma(mu, lambda, alpha, beta float64) *NormalGamma {
+	return &NormalGamma{mu: mu, lambda: lambda, alpha: alpha, beta: beta}
+}
+
+// Update conditions the posterior on a new observation.
+func (n *NormalGamma) Update(x float64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	delta := x - n.mu
+	n.beta += n.lambda * delta * delta / (2 * (n.lambda + 1))
+	n.mu += delta / (n.lambda + 1)
+	n.lambda++
+	n.alpha += 0.5
+}
+
+// Params returns the parameters of the posterior distribution.
+func (n *NormalGamma) Params() (mu, lambda, alpha, beta float64) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.mu, n.lambda, n.alpha, n.beta
+}
+
+// Mean returns the posterior mean of the observations' mean.
+func (n *NormalGamma) Mean() float64 {
+	mu, _, _, _ := n.Params()
+	return mu
+}
+
+// Predictive returns the posterior predictive distribution of the next
+// observation, a Student-t distribution.
+func (n *NormalGamma) Predictive() distuv.StudentsT {
+	mu, lambda, alpha, beta := n.Params()
+	return distuv.StudentsT{
+		Mu:    mu,
+		Sigma: math.Sqrt(beta * (lambda + 1) / (alpha * lambda)),
+		Nu:    2 * alpha,
+	}
+}
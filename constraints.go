@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+
+	"github.com/rlouf/gmc/node"
+)
+
+// SumToZero returns len(free)+1 variables that sum to zero: the free
+// variables followed by the opposite of their sum. This identifies
+// ANOVA-style effects that are otherwise only defined up to a constant.
+//
+// The constraint is enforced by construction rather than by rejecting the
+// proposals that violate it. Note that the last effect is a deterministic
+// function of the others, so its prior is wider than theirs.
+func (m *Model) SumToZero(free []node.Var) []node.Var {
+	if len(free) == 0 {
+		log.Panicf("needed at least one free variable")
+	}
+	last := &node.NegativeSumGate{
+		X: append([]node.Var(nil), free...),
+	}
+	m.deterministic = append(m.deterministic, last)
+
+	constrained := append([]node.Var(nil), free...)
+	return append(constrained, last)
+}
+
+// Ordered returns variables that are in strictly increasing order, e.g. the
+// cutpoints of an ordinal regression. The first one is equal to the first
+// free variable and each following one adds the exponential of the
+// corresponding free variable to its predecessor:
+//
+// x_0 = y_0
+// x_i = x_{i-1} + exp(y_i)
+//
+// The free variables can therefore take any real value, and the priors put
+// on them translate into priors on the first value and on the gaps.
+func (m *Model) Ordered(free []node.Var) []node.Var {
+	if len(free) == 0 {
+		log.Panicf("needed at least one free variable")
+	}
+	ordered := make([]node.Var, len(free))
+	ordered[0] = free[0]
+	for i := 1; i < len(free); i++ {
+		ordered[i] = m.Sum(ordered[i-1], m.Exp(free[i]))
+	}
+	return ordered
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sync"
+
+	"github.com/rlouf/gmc/rngsplit"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// CVOptions configures a k-fold cross-validation.
+type CVOptions struct {
+	NumFolds   int
+	NumSamples int       // number of posterior samples drawn on each fold, after burn-in
+	Initial    []float64 // initial values of the stochastic variables
+	Seed       uint64    // seed from which the random streams of the folds are derived
+}
+
+// A CVResult contains the held-out log predictive density of the
+// observations.
+type CVResult struct {
+	// ELPD is the sum of the log predictive densities of the held-out
+	// observations, the higher the better.
+	ELPD float64
+	// StdErr is the standard error of ELPD.
+	StdErr float64
+	// Pointwise contains the log predictive density of each observation,
+	// in the order of their index.
+	Pointwise []float64
+}
+
+// CrossValidate estimates the predictive performance of a model on new data
+// by k-fold cross-validation, for cases where the importance sampling
+// estimate of leave-one-out is unreliable.
+//
+// `build` must return the model in which the observations with the given
+// indices, between 0 and numObs-1, are observed, with one observed variable
+// per index in the same order. Observation i is assigned to fold
+// i % NumFolds. For each fold the model built on the other folds is sampled
+// with the Metropolis-Hastings sampler, and the density of the held-out
+// observations is averaged over the posterior samples of the model built on
+// the fold.
+//
+// The folds are fitted in parallel: `build` is called concurrently and must
+// return models that do not share nodes. Each fold samples with its own
+// random stream, derived from Seed with rngsplit.
+func CrossValidate(numObs int, build func(indices []int) *Model, opts CVOptions) CVResult {
+	if opts.NumFolds < 2 || opts.NumFolds > numObs {
+		log.Panicf("the number of folds must be between 2 and %d, got %d", numObs, opts.NumFolds)
+	}
+	if opts.NumSamples < 1 {
+		log.Panicf("needed at least one sample per fold, got %d", opts.NumSamples)
+	}
+
+	pointwise := make([]float64, numObs)
+	var wg sync.WaitGroup
+	for fold := 0; fold < opts.NumFolds; fold++ {
+		var train, test []int
+		for i := 0; i < numObs; i++ {
+			if i%opts.NumFolds == fold {
+				test = append(test, i)
+			} else {
+				train = append(train, i)
+			}
+		}
+
+		wg.Add(1)
+		go func(fold int, train, test []int) {
+			defer wg.Done()
+			fit := build(train)
+			// The nodes and the sampler share the model's source, so
+			// reseeding it changes the stream of the whole fit.
+			fit.Src.Seed(rngsplit.Derive(opts.Seed, uint64(fold)))
+			sampler := NewMetropolisHastingsSampler(fit)
+			samples := fit.Sample(opts.NumSamples, opts.Initial, *sampler.MetropolisHastingser)
+
+			heldOut := build(test)
+			if len(heldOut.observed) != len(test) {
+				log.Panicf("the model built on %d observations has %d observed variables", len(test), len(heldOut.observed))
+			}
+			logLik := heldOut.PointwiseLogLik(samples)
+			column := make([]float64, len(logLik))
+			for j, i := range test {
+				for s := range logLik {
+					column[s] = logLik[s][j]
+				}
+				// Each observation is written by a single fold.
+				pointwise[i] = floats.LogSumExp(column) - math.Log(float64(len(column)))
+			}
+		}(fold, train, test)
+	}
+	wg.Wait()
+
+	return CVResult{
+		ELPD:      floats.Sum(pointwise),
+		StdErr:    math.Sqrt(float64(numObs)) * stat.StdDev(pointwise, nil),
+		Pointwise: pointwise,
+	}
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NAValues lists the (case-insensitive) entries that are read as missing
+// values.
+var NAValues = []string{"", "na", "nan", "n/a", "null", "none"}
+
+// ReadCSV reads comma-separated records into a Table. The first record is
+// used as the header and must contain unique column names.
+//
+// The type of each column is inferred from its content: a column is numeric
+// when all its non-missing entries can be parsed as floats.
+func ReadCSV(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the csv input is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		for _, previous := range header[:i] {
+			if name == previous {
+				return nil, fmt.Errorf("duplicate column name in csv header: %s", name)
+			}
+		}
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{
+		Names:   header,
+		numeric: make(map[string][]float64),
+		text:    make(map[string][]string),
+		numRows: len(records),
+	}
+	for j, name := range header {
+		raw := make([]string, len(records))
+		for i, record := range records {
+			raw[i] = strings.TrimSpace(record[j])
+		}
+		if values, ok := parseFloats(raw); ok {
+			table.numeric[name] = values
+		} else {
+			table.text[name] = raw
+		}
+	}
+
+	return table, nil
+}
+
+// parseFloats converts the entries of a column to float64, mapping missing
+// values to NaN. It returns `false` as soon as an entry cannot be parsed.
+func parseFloats(raw []string) ([]float64, bool) {
+	values := make([]float64, len(raw))
+	for i, entry := range raw {
+		if isNA(entry) {
+			values[i] = math.NaN()
+			continue
+		}
+		v, err := strconv.ParseFloat(entry, 64)
+		if err != nil {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+func isNA(entry string) bool {
+	lowered := strings.ToLower(entry)
+	for _, na := range NAValues {
+		if lowered == na {
+			return true
+		}
+	}
+	return false
+}
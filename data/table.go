@@ -0,0 +1,77 @@
+package data
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Table holds columnar data read from an external source.
+//
+// Columns in which every non-missing entry parses as a number are stored as
+// float64, with missing entries represented by NaN. Any other column is kept
+// as strings.
+type Table struct {
+	Names []string
+
+	numeric map[string][]float64
+	text    map[string][]string
+	numRows int
+}
+
+// NumRows returns the number of records in the table.
+func (t *Table) NumRows() int {
+	return t.numRows
+}
+
+// IsNumeric returns `true` if the column was inferred to be numeric.
+func (t *Table) IsNumeric(name string) bool {
+	_, ok := t.numeric[name]
+	return ok
+}
+
+// Float returns the values of a numeric column. Missing values are NaN.
+func (t *Table) Float(name string) ([]float64, error) {
+	col, ok := t.numeric[name]
+	if !ok {
+		if _, isText := t.text[name]; isText {
+			return nil, fmt.Errorf("column %s is not numeric", name)
+		}
+		return nil, fmt.Errorf("the column does not exist: %s", name)
+	}
+	return col, nil
+}
+
+// Strings returns the values of a column as strings. Numeric columns are
+// formatted back, missing values become the empty string.
+func (t *Table) Strings(name string) ([]string, error) {
+	if col, ok := t.text[name]; ok {
+		return col, nil
+	}
+	col, ok := t.numeric[name]
+	if !ok {
+		return nil, fmt.Errorf("the column does not exist: %s", name)
+	}
+	formatted := make([]string, len(col))
+	for i, v := range col {
+		if !math.IsNaN(v) {
+			formatted[i] = fmt.Sprint(v)
+		}
+	}
+	return formatted, nil
+}
+
+// DropNA returns the values of a numeric column with the missing values
+// removed.
+func (t *Table) DropNA(name string) ([]float64, error) {
+	col, err := t.Float(name)
+	if err != nil {
+		return nil, err
+	}
+	var values []float64
+	for _, v := range col {
+		if !math.IsNaN(v) {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
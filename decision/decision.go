@@ -0,0 +1,77 @@
+// Package decision helps choosing between actions whose consequences
+// depend on the unknown parameters of a model, by maximizing the expected
+// utility under the posterior distribution.
+package decision
+
+import (
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/rlouf/gmc/trace"
+)
+
+// A Utility returns the utility of taking the action with index `action`
+// when the model's parameters take the values in `params`.
+type Utility func(action int, params map[string]float64) float64
+
+// A Result contains the expected utility of each candidate action and the
+// Bayes-optimal action.
+type Result struct {
+	Best            int       // index of the action with the highest expected utility
+	ExpectedUtility []float64 // Monte Carlo estimate of each action's expected utility
+	StdErr          []float64 // Monte Carlo standard error of each estimate
+}
+
+// Optimal computes the expected utility of numActions candidate actions by
+// averaging the utility over the draws of the trace, and returns the action
+// that maximizes it.
+//
+// The standard errors assume independent draws; they are underestimated
+// when the samples are autocorrelated.
+func Optimal(samples trace.Trace, numActions int, utility Utility) Result {
+	if numActions < 1 {
+		log.Panicf("needed at least one action, got %d", numActions)
+	}
+	numDraws := -1
+	for name, draws := range samples {
+		if numDraws == -1 {
+			numDraws = len(draws)
+		} else if len(draws) != numDraws {
+			log.Panicf("variable %s has %d samples, expected %d", name, len(draws), numDraws)
+		}
+	}
+	if numDraws < 1 {
+		log.Panicf("the trace is empty")
+	}
+
+	utilities := make([][]float64, numActions)
+	for a := range utilities {
+		utilities[a] = make([]float64, numDraws)
+	}
+	params := make(map[string]float64)
+	for i := 0; i < numDraws; i++ {
+		for name, draws := range samples {
+			params[name] = draws[i]
+		}
+		for a := 0; a < numActions; a++ {
+			utilities[a][i] = utility(a, params)
+		}
+	}
+
+	result := Result{
+		ExpectedUtility: make([]float64, numActions),
+		StdErr:          make([]float64, numActions),
+	}
+	for a, u := range utilities {
+		mean, std := stat.MeanStdDev(u, nil)
+		result.ExpectedUtility[a] = mean
+		result.StdErr[a] = std / math.Sqrt(float64(numDraws))
+		if mean > result.ExpectedUtility[result.Best] {
+			result.Best = a
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// ExpectedInformationGain estimates, for each candidate design, the expected
+// reduction in entropy of the stochastic variables' distribution brought by
+// observing the outcome of the experiment. The design with the highest gain
+// is the most informative next measurement.
+//
+// `build` must return the model of the experiment for a given design; the
+// value of its observed variables is irrelevant since they are replaced by
+// simulated outcomes. The gain is estimated by nested Monte Carlo over the
+// prior predictive distribution, with numOuter simulated outcomes and
+// numInner prior draws to estimate their marginal likelihood:
+//
+// "On Nesting Monte Carlo Estimators" (Rainforth et al. 2018)
+// https://arxiv.org/abs/1709.06181
+//
+// The estimator is biased upwards by a term that decreases as numInner
+// grows.
+func ExpectedInformationGain(build func(design float64) *Model, designs []float64, numOuter, numInner int) []float64 {
+	if numOuter < 1 || numInner < 1 {
+		log.Panicf("needed at least one outer and inner sample, got %d and %d", numOuter, numInner)
+	}
+
+	gains := make([]float64, len(designs))
+	logLiks := make([]float64, numInner)
+	for d, design := range designs {
+		m := build(design)
+		if len(m.observed) == 0 {
+			log.Panicf("the model for design %f has no observed variable", design)
+		}
+
+		var gain float64
+		for n := 0; n < numOuter; n++ {
+			m.samplePrior()
+			for _, o := range m.observed {
+				o.SetValue(o.Rand())
+			}
+			logLik := m.logLikelihood()
+
+			for i := range logLiks {
+				m.samplePrior()
+				logLiks[i] = m.logLikelihood()
+			}
+			logMarginal := floats.LogSumExp(logLiks) - math.Log(float64(numInner))
+
+			gain += logLik - logMarginal
+		}
+		gains[d] = gain / float64(numOuter)
+	}
+
+	return gains
+}
+
+// samplePrior sets the stochastic variables to values drawn from their
+// prior distribution.
+func (m *Model) samplePrior() {
+	for _, v := range m.stochastic {
+		v.SetValue(v.Rand())
+	}
+}
+
+// logLikelihood returns the log-probability of the observed variables
+// given the current value of the stochastic variables.
+func (m *Model) logLikelihood() float64 {
+	var logprob float64
+	for _, o := range m.observed {
+		logprob += o.LogProb()
+	}
+	return logprob
+}
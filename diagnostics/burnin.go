@@ -0,0 +1,91 @@
+package diagnostics
+
+import (
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/rlouf/gmc/trace"
+)
+
+// Geweke returns the z-score of the difference between the mean of the
+// first `first` fraction of the chain and the mean of its last `last`
+// fraction. The variance of each mean accounts for autocorrelation through
+// the effective sample size. Values of |z| larger than 2 indicate that the
+// beginning of the chain has not reached the stationary distribution:
+//
+// "Evaluating the accuracy of sampling-based approaches to the calculation
+// of posterior moments" (Geweke 1992)
+//
+// The mean of a constant part of the chain is known exactly: z is 0 when
+// both parts are constant and equal, and infinite when they differ.
+func Geweke(samples []float64, first, last float64) float64 {
+	if first <= 0 || last <= 0 || first+last > 1 {
+		log.Panicf("the fractions must be positive and sum to at most 1, got %f and %f", first, last)
+	}
+	n := len(samples)
+	a := samples[:int(first*float64(n))]
+	b := samples[n-int(last*float64(n)):]
+
+	meanA, varA := stat.MeanVariance(a, nil)
+	meanB, varB := stat.MeanVariance(b, nil)
+	difference := meanA - meanB
+	if variance := meanVariance(a, varA) + meanVariance(b, varB); variance > 0 {
+		return difference / math.Sqrt(variance)
+	}
+	if difference == 0 {
+		return 0
+	}
+	return math.Copysign(math.Inf(1), difference)
+}
+
+// meanVariance returns the variance of the mean of the samples, whose
+// variance is given. The effective sample size is not defined when the
+// variance is 0.
+func meanVariance(samples []float64, variance float64) float64 {
+	if variance == 0 {
+		return 0
+	}
+	return variance / EffectiveSampleSize(samples)
+}
+
+// BurnIn estimates the number of samples at the beginning of a chain that
+// should be discarded because the chain had not converged yet.
+//
+// It discards 0%, 10%, ..., 50% of the chain and returns the smallest number
+// of samples for which the Geweke diagnostic of the remaining chain, with
+// the first 10% compared to the last 50%, is below 2 in absolute value.
+// The second return value is false when no such number was found, in which
+// case the chain should be run longer.
+//
+// A chain that never moves needs no burn-in and is reported as converged,
+// whether its variable is constant or the sampler is stuck: check the
+// acceptance rate to tell them apart.
+func BurnIn(samples []float64) (int, bool) {
+	n := len(samples)
+	for step := 0; step <= 5; step++ {
+		discard := step * n / 10
+		z := Geweke(samples[discard:], 0.1, 0.5)
+		if math.Abs(z) < 2 {
+			return discard, true
+		}
+	}
+	return n / 2, false
+}
+
+// TraceBurnIn estimates the number of samples to discard from all the
+// variables of the trace: the largest burn-in of its variables. The second
+// return value is false if the burn-in could not be estimated for one of
+// the variables.
+func TraceBurnIn(t trace.Trace) (int, bool) {
+	burnIn, converged := 0, true
+	for _, name := range t.Names() {
+		b, ok := BurnIn(t[name])
+		if b > burnIn {
+			burnIn = b
+		}
+		converged = converged && ok
+	}
+	return burnIn, converged
+}
@@ -0,0 +1,50 @@
+package diagnostics
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestBurnIn(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	samples := make([]float64, 1000)
+	for i := range samples {
+		samples[i] = src.NormFloat64()
+		// The first 20% of the chain drift towards the stationary
+		// distribution.
+		if i < 200 {
+			samples[i] += 10 * float64(200-i) / 200
+		}
+	}
+	burnIn, ok := BurnIn(samples)
+	if !ok || burnIn < 100 || burnIn > 300 {
+		t.Errorf("got a burn-in of %d (converged: %t), want about 200", burnIn, ok)
+	}
+
+	if burnIn, ok := BurnIn(samples[300:]); !ok || burnIn != 0 {
+		t.Errorf("got a burn-in of %d (converged: %t) for a stationary chain, want 0", burnIn, ok)
+	}
+}
+
+func TestBurnInConstant(t *testing.T) {
+	samples := make([]float64, 100)
+	for i := range samples {
+		samples[i] = 3
+	}
+	if burnIn, ok := BurnIn(samples); !ok || burnIn != 0 {
+		t.Errorf("got a burn-in of %d (converged: %t) for a constant chain, want 0", burnIn, ok)
+	}
+
+	// The chain is stuck at a different value at the beginning.
+	for i := 0; i < 20; i++ {
+		samples[i] = 5
+	}
+	if z := Geweke(samples, 0.1, 0.5); !math.IsInf(z, 1) {
+		t.Errorf("got z = %f, want +Inf", z)
+	}
+	if burnIn, ok := BurnIn(samples); !ok || burnIn != 20 {
+		t.Errorf("got a burn-in of %d (converged: %t), want 20", burnIn, ok)
+	}
+}
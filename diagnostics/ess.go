@@ -0,0 +1,57 @@
+// Package diagnostics contains tools to assess the quality of the samples
+// drawn by the samplers.
+package diagnostics
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// MinEffectiveSampleSize is the effective sample size below which estimates
+// of the posterior mean are considered unreliable.
+const MinEffectiveSampleSize = 100
+
+// EffectiveSampleSize estimates the number of independent samples that would
+// give the same precision on the mean as the autocorrelated samples of a
+// chain. It returns NaN when the chain is constant.
+//
+// The autocorrelation time is computed with Geyer's initial positive
+// sequence estimator:
+//
+// "Practical Markov Chain Monte Carlo" (Geyer 1992)
+// https://doi.org/10.1214/ss/1177011137
+func EffectiveSampleSize(samples []float64) float64 {
+	n := len(samples)
+	if n < 4 {
+		return float64(n)
+	}
+
+	mean := stat.Mean(samples, nil)
+	autocovariance := func(lag int) float64 {
+		var sum float64
+		for i := 0; i+lag < n; i++ {
+			sum += (samples[i] - mean) * (samples[i+lag] - mean)
+		}
+		return sum / float64(n)
+	}
+
+	variance := autocovariance(0)
+	if variance == 0 {
+		return math.NaN()
+	}
+
+	// Sum the autocorrelations by pairs until a pair becomes negative, after
+	// which the estimates are dominated by noise.
+	var sum float64
+	for lag := 0; lag+1 < n; lag += 2 {
+		pair := (autocovariance(lag) + autocovariance(lag+1)) / variance
+		if pair < 0 {
+			break
+		}
+		sum += pair
+	}
+	tau := 2*sum - 1
+
+	return float64(n) / tau
+}
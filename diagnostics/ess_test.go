@@ -0,0 +1,44 @@
+package diagnostics
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestEffectiveSampleSize(t *testing.T) {
+	// The sum of the autocorrelations stops after the pair of lags 2 and
+	// 3, the first negative one; computed independently.
+	samples := []float64{0.3, 0.5, 0.9, 1.2, 1.0, 0.7, 0.2, -0.1, -0.4, -0.2, 0.1, 0.6}
+	if got, want := EffectiveSampleSize(samples), 4.222784810126583; math.Abs(got-want) > 1e-12 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+
+	if got := EffectiveSampleSize([]float64{2, 2, 2, 2, 2}); !math.IsNaN(got) {
+		t.Errorf("got %f for a constant chain, want NaN", got)
+	}
+}
+
+// TestEffectiveSampleSizeAR1 checks the effective sample size of an
+// autoregressive chain of coefficient rho against its asymptotic value
+// n (1 - rho) / (1 + rho).
+func TestEffectiveSampleSizeAR1(t *testing.T) {
+	const rho = 0.8
+	samples := ar1(100000, rho, 1)
+	want := float64(len(samples)) * (1 - rho) / (1 + rho)
+	if got := EffectiveSampleSize(samples); math.Abs(got-want)/want > 0.05 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
+
+// ar1 returns an autoregressive chain of coefficient rho with standard
+// normal innovations.
+func ar1(n int, rho float64, seed uint64) []float64 {
+	src := rand.New(rand.NewSource(seed))
+	samples := make([]float64, n)
+	for i := 1; i < n; i++ {
+		samples[i] = rho*samples[i-1] + src.NormFloat64()
+	}
+	return samples
+}
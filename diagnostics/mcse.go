@@ -0,0 +1,97 @@
+package diagnostics
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// MCSE returns the Monte Carlo standard error of the estimate of the
+// posterior mean by the mean of the samples of a chain.
+func MCSE(samples []float64) float64 {
+	return FunctionalMCSE(samples, func(x float64) float64 { return x })
+}
+
+// FunctionalESS returns the effective sample size of the estimate of the
+// posterior expectation of f by the mean of f over the samples of a chain.
+// The effective sample size differs from one functional to the other: the
+// samples of a chain can estimate the mean well and the second moment, or
+// the probability of an event, poorly.
+func FunctionalESS(samples []float64, f func(float64) float64) float64 {
+	return EffectiveSampleSize(apply(samples, f))
+}
+
+// FunctionalMCSE returns the Monte Carlo standard error of the estimate of
+// the posterior expectation of f by the mean of f over the samples of a
+// chain.
+func FunctionalMCSE(samples []float64, f func(float64) float64) float64 {
+	values := apply(samples, f)
+	return stat.StdDev(values, nil) / math.Sqrt(EffectiveSampleSize(values))
+}
+
+// QuantileESS returns the effective sample size of the estimate of the p-th
+// quantile of the posterior distribution by the p-th quantile of the samples
+// of a chain. It is the effective sample size of the indicator of the
+// samples that are lower than or equal to the estimate, and is usually much
+// smaller in the tails than in the bulk of the distribution:
+//
+// "Rank-normalization, folding, and localization: An improved R-hat for
+// assessing convergence of MCMC" (Vehtari et al. 2021)
+// https://doi.org/10.1214/20-BA1221
+func QuantileESS(samples []float64, p float64) float64 {
+	checkProbability(p)
+	quantile := stat.Quantile(p, stat.Empirical, sorted(samples), nil)
+	return FunctionalESS(samples, func(x float64) float64 {
+		if x <= quantile {
+			return 1
+		}
+		return 0
+	})
+}
+
+// QuantileMCSE returns the Monte Carlo standard error of the estimate of the
+// p-th quantile of the posterior distribution by the p-th quantile of the
+// samples of a chain, so that the endpoints of credible intervals can be
+// reported with their precision.
+//
+// The standard error of the probability of the samples below the estimate,
+// given by QuantileESS, is mapped back to the scale of the samples through
+// their quantiles: the result is half of the distance between the quantiles
+// one standard error below and above p.
+func QuantileMCSE(samples []float64, p float64) float64 {
+	ess := QuantileESS(samples, p)
+	if math.IsNaN(ess) {
+		return math.NaN()
+	}
+	// The probability of the samples below the estimate follows a Beta
+	// distribution, whose quantiles one standard deviation away from the
+	// median are Φ(-1) and Φ(1).
+	dist := distuv.Beta{Alpha: ess*p + 1, Beta: ess*(1-p) + 1}
+	s := sorted(samples)
+	lower := stat.Quantile(dist.Quantile(distuv.UnitNormal.CDF(-1)), stat.Empirical, s, nil)
+	upper := stat.Quantile(dist.Quantile(distuv.UnitNormal.CDF(1)), stat.Empirical, s, nil)
+	return (upper - lower) / 2
+}
+
+func checkProbability(p float64) {
+	if p <= 0 || p >= 1 {
+		log.Panicf("the probability of the quantile must be in (0, 1), got %f", p)
+	}
+}
+
+func apply(samples []float64, f func(float64) float64) []float64 {
+	values := make([]float64, len(samples))
+	for i, x := range samples {
+		values[i] = f(x)
+	}
+	return values
+}
+
+func sorted(samples []float64) []float64 {
+	s := append([]float64(nil), samples...)
+	sort.Float64s(s)
+	return s
+}
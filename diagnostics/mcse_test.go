@@ -0,0 +1,38 @@
+package diagnostics
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// TestMCSE checks the standard error of the mean of an autoregressive chain
+// of coefficient rho against its asymptotic value, the standard deviation
+// of the chain divided by the square root of n (1 - rho) / (1 + rho).
+func TestMCSE(t *testing.T) {
+	const rho = 0.8
+	samples := ar1(100000, rho, 1)
+	ess := float64(len(samples)) * (1 - rho) / (1 + rho)
+	want := stat.StdDev(samples, nil) / math.Sqrt(ess)
+	if got := MCSE(samples); math.Abs(got-want)/want > 0.05 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
+
+// TestQuantileMCSE checks the standard error of the median of independent
+// standard normal draws against its asymptotic value
+// sqrt(p (1 - p) / n) / φ(0).
+func TestQuantileMCSE(t *testing.T) {
+	const n = 100000
+	samples := ar1(n, 0, 2)
+
+	if got := QuantileESS(samples, 0.5); math.Abs(got-n)/n > 0.05 {
+		t.Errorf("got a quantile effective sample size of %f, want %d", got, n)
+	}
+	want := math.Sqrt(0.25/n) / distuv.UnitNormal.Prob(0)
+	if got := QuantileMCSE(samples, 0.5); math.Abs(got-want)/want > 0.1 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package diagnostics
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// A RunLength is the number of samples recommended by the Raftery-Lewis
+// diagnostic.
+type RunLength struct {
+	Thin       int     // thinning interval that makes the chain approximately Markovian
+	BurnIn     int     // number of samples to discard at the beginning of the chain
+	Total      int     // number of samples to draw after burn-in
+	MinSize    int     // number of samples needed if they were independent
+	Dependence float64 // (BurnIn + Total) / MinSize, values > 5 indicate strong autocorrelation
+}
+
+// RafteryLewis estimates from a pilot chain how many samples are needed to
+// estimate the quantile q of a variable to within +/- r with probability s,
+// e.g. q = 0.025, r = 0.005, s = 0.95.
+//
+// The chain is reduced to the binary sequence of indicators that the
+// samples are below the quantile, which is thinned until it behaves like a
+// first-order Markov chain. The run length follows from the transition
+// probabilities of this two-state chain, so RafteryLewis panics if the
+// chain never crosses the quantile:
+//
+// "How many iterations in the Gibbs sampler?" (Raftery & Lewis 1992)
+func RafteryLewis(samples []float64, q, r, s float64) RunLength {
+	phi := distuv.UnitNormal.Quantile((s + 1) / 2)
+	minSize := int(math.Ceil(q * (1 - q) * phi * phi / (r * r)))
+	if len(samples) < minSize {
+		log.Panicf("the pilot chain needs at least %d samples, got %d", minSize, len(samples))
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	quantile := stat.Quantile(q, stat.Empirical, sorted, nil)
+	indicators := make([]int, len(samples))
+	for i, x := range samples {
+		if x <= quantile {
+			indicators[i] = 1
+		}
+	}
+
+	thin := 1
+	for ; thin < len(samples)/3; thin++ {
+		if isFirstOrderMarkov(thinned(indicators, thin)) {
+			break
+		}
+	}
+
+	var counts [2][2]float64
+	binary := thinned(indicators, thin)
+	for t := 1; t < len(binary); t++ {
+		counts[binary[t-1]][binary[t]]++
+	}
+	// A chain that never leaves one side of the quantile, e.g. a constant
+	// or stuck chain, has no transition probabilities to estimate.
+	fromBelow := counts[1][0] + counts[1][1]
+	fromAbove := counts[0][0] + counts[0][1]
+	if fromBelow == 0 || fromAbove == 0 || counts[0][1]+counts[1][0] == 0 {
+		log.Panicf("the pilot chain never crosses the quantile %g, it is stuck or constant", quantile)
+	}
+	alpha := counts[0][1] / fromAbove
+	beta := counts[1][0] / fromBelow
+
+	const epsilon = 0.001
+	burnIn := math.Log(epsilon*(alpha+beta)/math.Max(alpha, beta)) / math.Log(math.Abs(1-alpha-beta))
+	total := (2 - alpha - beta) * alpha * beta / math.Pow(alpha+beta, 3) * phi * phi / (r * r)
+
+	result := RunLength{
+		Thin:    thin,
+		BurnIn:  int(math.Ceil(burnIn)) * thin,
+		Total:   int(math.Ceil(total)) * thin,
+		MinSize: minSize,
+	}
+	result.Dependence = float64(result.BurnIn+result.Total) / float64(minSize)
+
+	return result
+}
+
+func thinned(indicators []int, k int) []int {
+	var kept []int
+	for i := 0; i < len(indicators); i += k {
+		kept = append(kept, indicators[i])
+	}
+	return kept
+}
+
+// isFirstOrderMarkov compares a first-order and a second-order Markov model
+// of a binary sequence with the BIC, and returns `true` if the first-order
+// model is preferred.
+func isFirstOrderMarkov(binary []int) bool {
+	var counts [2][2][2]float64
+	for t := 2; t < len(binary); t++ {
+		counts[binary[t-2]][binary[t-1]][binary[t]]++
+	}
+
+	var g2 float64
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			for l := 0; l < 2; l++ {
+				if counts[i][j][l] == 0 {
+					continue
+				}
+				nij := counts[i][j][0] + counts[i][j][1]
+				njl := counts[0][j][l] + counts[1][j][l]
+				nj := counts[0][j][0] + counts[0][j][1] + counts[1][j][0] + counts[1][j][1]
+				fitted := nij * njl / nj
+				g2 += 2 * counts[i][j][l] * math.Log(counts[i][j][l]/fitted)
+			}
+		}
+	}
+	bic := g2 - 2*math.Log(float64(len(binary)-2))
+
+	return bic < 0
+}
@@ -0,0 +1,40 @@
+package diagnostics
+
+import (
+	"math"
+	"testing"
+)
+
+// TestRafteryLewisIndependent checks the run length of an independent chain
+// against the example of Raftery & Lewis (1992): estimating the 0.025
+// quantile to within ±0.005 with probability 0.95 takes 3746 independent
+// samples, and an independent chain needs no thinning nor burn-in beyond
+// that.
+func TestRafteryLewisIndependent(t *testing.T) {
+	result := RafteryLewis(ar1(20000, 0, 1), 0.025, 0.005, 0.95)
+	if result.MinSize != 3746 {
+		t.Errorf("got a minimum size of %d, want 3746", result.MinSize)
+	}
+	if result.Thin != 1 {
+		t.Errorf("got a thinning interval of %d, want 1", result.Thin)
+	}
+	if math.Abs(result.Dependence-1) > 0.2 {
+		t.Errorf("got a dependence factor of %f, want about 1", result.Dependence)
+	}
+}
+
+// TestRafteryLewisConstant checks that a chain that never crosses the
+// quantile panics rather than returning run lengths computed from NaN.
+func TestRafteryLewisConstant(t *testing.T) {
+	samples := make([]float64, 5000)
+	for i := range samples {
+		samples[i] = 3
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	RafteryLewis(samples, 0.025, 0.005, 0.95)
+}
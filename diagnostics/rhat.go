@@ -0,0 +1,154 @@
+package diagnostics
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// RHat computes the rank-normalized split potential scale reduction factor
+// of several chains sampling the same variable. Values close to 1 indicate
+// that the chains have mixed; values above 1.01 indicate they have not:
+//
+// "Rank-normalization, folding, and localization: An improved R-hat for
+// assessing convergence of MCMC" (Vehtari et al. 2021)
+// https://doi.org/10.1214/20-BA1221
+//
+// Each chain is split in two halves, which detects trends within chains.
+// The draws are replaced by the normal scores of their rank among all the
+// draws, which makes the diagnostic robust to heavy tails, and R-hat is
+// computed on them (bulk R-hat) and on the ranks of their distance to the
+// median, which detects chains that differ in scale rather than location
+// (tail R-hat). RHat returns the larger of the two.
+//
+// It needs a single chain at least and returns NaN when the chains are too
+// short to be split.
+func RHat(chains [][]float64) float64 {
+	if len(chains) < 1 {
+		log.Panicf("needed at least 1 chain, got %d", len(chains))
+	}
+	n := checkChains(chains)
+	if n < 4 {
+		return math.NaN()
+	}
+
+	split := splitChains(chains)
+	bulk := classicRHat(rankNormalize(split))
+
+	var all []float64
+	for _, chain := range chains {
+		all = append(all, chain...)
+	}
+	sort.Float64s(all)
+	// The average of the two middle draws when their number is even.
+	median := (all[(len(all)-1)/2] + all[len(all)/2]) / 2
+	folded := make([][]float64, len(split))
+	for i, chain := range split {
+		folded[i] = make([]float64, len(chain))
+		for j, x := range chain {
+			folded[i][j] = math.Abs(x - median)
+		}
+	}
+	tail := classicRHat(rankNormalize(folded))
+
+	return math.Max(bulk, tail)
+}
+
+// ClassicRHat computes the potential scale reduction factor of several
+// chains sampling the same variable: the ratio between an estimate of the
+// posterior variance that pools the chains and the variance within each
+// chain:
+//
+// "Inference from Iterative Simulation Using Multiple Sequences" (Gelman &
+// Rubin 1992)
+// https://doi.org/10.1214/ss/1177011136
+//
+// It misses chains that differ in their tails or that have not converged
+// within themselves; prefer RHat.
+func ClassicRHat(chains [][]float64) float64 {
+	if len(chains) < 2 {
+		log.Panicf("needed at least 2 chains, got %d", len(chains))
+	}
+	if n := checkChains(chains); n < 2 {
+		return math.NaN()
+	}
+	return classicRHat(chains)
+}
+
+// checkChains panics if the chains do not have the same length, and returns
+// this length.
+func checkChains(chains [][]float64) int {
+	n := len(chains[0])
+	for i, chain := range chains {
+		if len(chain) != n {
+			log.Panicf("chain %d has %d samples, expected %d", i, len(chain), n)
+		}
+	}
+	return n
+}
+
+func classicRHat(chains [][]float64) float64 {
+	n := len(chains[0])
+	means := make([]float64, len(chains))
+	var within float64
+	for i, chain := range chains {
+		mean, variance := stat.MeanVariance(chain, nil)
+		means[i] = mean
+		within += variance
+	}
+	within /= float64(len(chains))
+	between := float64(n) * stat.Variance(means, nil)
+
+	pooled := float64(n-1)/float64(n)*within + between/float64(n)
+	return math.Sqrt(pooled / within)
+}
+
+// splitChains splits every chain in two halves, dropping the middle sample
+// of chains of odd length.
+func splitChains(chains [][]float64) [][]float64 {
+	half := len(chains[0]) / 2
+	split := make([][]float64, 0, 2*len(chains))
+	for _, chain := range chains {
+		split = append(split, chain[:half], chain[len(chain)-half:])
+	}
+	return split
+}
+
+// rankNormalize replaces every draw by the normal score of its rank among
+// all the draws, with Blom's offset: Φ^-1((r - 3/8) / (S + 1/4)). Tied
+// draws get their average rank.
+func rankNormalize(chains [][]float64) [][]float64 {
+	type draw struct {
+		value        float64
+		chain, index int
+	}
+	var draws []draw
+	for i, chain := range chains {
+		for j, x := range chain {
+			draws = append(draws, draw{x, i, j})
+		}
+	}
+	sort.Slice(draws, func(a, b int) bool { return draws[a].value < draws[b].value })
+
+	normalized := make([][]float64, len(chains))
+	for i, chain := range chains {
+		normalized[i] = make([]float64, len(chain))
+	}
+	size := float64(len(draws))
+	for start := 0; start < len(draws); {
+		end := start + 1
+		for end < len(draws) && draws[end].value == draws[start].value {
+			end++
+		}
+		rank := float64(start+end+1) / 2 // average of the ranks start+1, ..., end
+		score := distuv.UnitNormal.Quantile((rank - 3.0/8) / (size + 1.0/4))
+		for _, d := range draws[start:end] {
+			normalized[d.chain][d.index] = score
+		}
+		start = end
+	}
+	return normalized
+}
@@ -0,0 +1,51 @@
+package diagnostics
+
+import (
+	"math"
+	"testing"
+)
+
+// The reference values were computed independently, following the
+// definitions of Gelman & Rubin (1992) and Vehtari et al. (2021).
+
+func TestClassicRHat(t *testing.T) {
+	chains := [][]float64{
+		{1.2, 0.4, 2.3, 1.8, 0.9, 1.5, 2.9},
+		{3.1, 2.6, 4.0, 3.3, 2.2, 3.8, 2.9},
+		{1.9, 2.5, 0.7, 3.6, 1.4, 2.1, 2.8},
+	}
+	if got, want := ClassicRHat(chains), 1.3334946157111414; math.Abs(got-want) > 1e-12 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+	// The middle sample of each chain is dropped, and the tied draws 2.9
+	// share their rank.
+	if got, want := RHat(chains), 1.2364110270395179; math.Abs(got-want) > 1e-12 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
+
+// TestRHatScale checks that chains centered on the same value but with
+// different scales are detected by the improved R-hat only, through its
+// folded (tail) part. The draws are folded around their median, the average
+// of the two middle draws.
+func TestRHatScale(t *testing.T) {
+	chains := [][]float64{
+		{-0.1, 0.2, -0.3, 0.1, 0.0, -0.2, 0.3, 0.15},
+		{-2.0, 3.1, -1.5, 2.4, -2.8, 1.7, -0.9, 2.2},
+	}
+	if got, want := ClassicRHat(chains), 0.9419097539379633; math.Abs(got-want) > 1e-12 {
+		t.Errorf("classic: got %f, want %f", got, want)
+	}
+	if got, want := RHat(chains), 1.7339897854093764; math.Abs(got-want) > 1e-12 {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
+
+func TestRHatShortChains(t *testing.T) {
+	if got := RHat([][]float64{{1, 2, 3}, {4, 5, 6}}); !math.IsNaN(got) {
+		t.Errorf("got %f for chains too short to be split, want NaN", got)
+	}
+	if got := ClassicRHat([][]float64{{1}, {2}}); !math.IsNaN(got) {
+		t.Errorf("got %f for chains of 1 sample, want NaN", got)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log"
+
+	"github.com/rlouf/gmc/node"
+)
+
+type generatedQuantity struct {
+	name     string
+	variable node.Var
+}
+
+// Generated registers a quantity that is derived from the model's variables
+// and stored in the trace under `name`.
+//
+// Unlike deterministic nodes that other nodes depend on, generated quantities
+// are not evaluated while the sampler explores the posterior: they are
+// computed once for every stored sample, which keeps expensive derived
+// quantities out of the sampling loop.
+func (m *Model) Generated(name string, variable node.Var) {
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.generated = append(m.generated, generatedQuantity{name: name, variable: variable})
+}
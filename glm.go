@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/rlouf/gmc/node"
+	"github.com/rlouf/gmc/trace"
+)
+
+// A Family is the distribution of the outcome of a generalized linear model
+// along with the link between its mean and the linear predictor.
+type Family int
+
+const (
+	// BinomialLogit models the number of successes among a number of trials,
+	// with a logistic link: p = logistic(eta).
+	BinomialLogit Family = iota
+	// PoissonLog models counts with a log link: lambda = exp(eta).
+	PoissonLog
+)
+
+// GLMConfig configures a generalized linear model.
+type GLMConfig struct {
+	Family Family
+
+	// Names are the names of the coefficients of the covariates. They
+	// default to "beta_<j>".
+	Names []string
+
+	// PriorScale is the standard deviation of the normal prior on the
+	// intercept and the coefficients. It defaults to 2.5 when the covariates
+	// are standardized.
+	PriorScale float64
+
+	// Standardize centers each covariate and divides it by its standard
+	// deviation before fitting, so that a single weakly informative prior
+	// scale suits every coefficient whatever the units of the covariates.
+	// The trace then contains the coefficients of the standardized
+	// covariates; see GLM.OriginalScale.
+	Standardize bool
+
+	// Trials is the number of trials of each binomial outcome. Every
+	// outcome is a single trial when Trials is nil.
+	Trials []float64
+
+	// Offset is a known term added to the linear predictor of each
+	// observation, e.g. the logarithm of the exposure time or of the
+	// population in a model of rates.
+	Offset []float64
+
+	// Weights multiply the contribution of each observation to the
+	// log-likelihood, e.g. to account for observations that stand for
+	// several identical cases. See Model.SetPower.
+	Weights []float64
+}
+
+// A GLM is a generalized linear model of the outcomes y given the covariates
+// x, where x[i] contains the covariates of the i-th observation. The linear
+// predictor of the i-th observation is:
+//
+// eta_i = intercept + sum_j beta_j * x[i][j] + offset_i
+//
+// The outcomes are named "y_<i>" and the intercept "intercept".
+type GLM struct {
+	*Model
+	Intercept    *node.Normal
+	Coefficients []*node.Normal
+
+	// means and stdDevs of the covariates when they are standardized
+	means   []float64
+	stdDevs []float64
+}
+
+// NewGLM builds the generalized linear model of the outcomes y given the
+// covariates x.
+func NewGLM(x [][]float64, y []float64, config GLMConfig) *GLM {
+	if len(x) != len(y) {
+		log.Panicf("needed %d rows of covariates, got %d", len(y), len(x))
+	}
+	if config.Standardize && config.PriorScale == 0 {
+		config.PriorScale = 2.5
+	}
+	if config.PriorScale <= 0 {
+		log.Panicf("The prior scale must be strictly positive, got %f", config.PriorScale)
+	}
+	checkLength("trials", config.Trials, len(y))
+	checkLength("offsets", config.Offset, len(y))
+	checkLength("weights", config.Weights, len(y))
+	numCovariates := 0
+	if len(x) > 0 {
+		numCovariates = len(x[0])
+	}
+	if config.Names != nil && len(config.Names) != numCovariates {
+		log.Panicf("needed %d coefficient names, got %d", numCovariates, len(config.Names))
+	}
+
+	glm := &GLM{Model: NewModel()}
+	m := glm.Model
+	if config.Standardize {
+		glm.means = make([]float64, numCovariates)
+		glm.stdDevs = make([]float64, numCovariates)
+		column := make([]float64, len(x))
+		for j := 0; j < numCovariates; j++ {
+			for i, row := range x {
+				column[i] = row[j]
+			}
+			glm.means[j], glm.stdDevs[j] = stat.MeanStdDev(column, nil)
+			if glm.stdDevs[j] == 0 {
+				log.Panicf("covariate %d is constant and cannot be standardized", j)
+			}
+		}
+	}
+
+	zero, scale := m.Constant(0), m.Constant(config.PriorScale)
+	glm.Intercept = m.Normal("intercept", zero, scale)
+	glm.Coefficients = make([]*node.Normal, numCovariates)
+	for j := range glm.Coefficients {
+		name := fmt.Sprintf("beta_%d", j)
+		if config.Names != nil {
+			name = config.Names[j]
+		}
+		glm.Coefficients[j] = m.Normal(name, zero, scale)
+	}
+
+	for i, row := range x {
+		if len(row) != numCovariates {
+			log.Panicf("row %d has %d covariates, expected %d", i, len(row), numCovariates)
+		}
+		var eta node.Var = glm.Intercept
+		for j, value := range row {
+			if config.Standardize {
+				value = (value - glm.means[j]) / glm.stdDevs[j]
+			}
+			eta = m.Sum(eta, m.Prod(glm.Coefficients[j], m.Constant(value)))
+		}
+		if config.Offset != nil {
+			eta = m.Sum(eta, m.Constant(config.Offset[i]))
+		}
+
+		name := fmt.Sprintf("y_%d", i)
+		var outcome node.RandVar
+		switch config.Family {
+		case BinomialLogit:
+			trials := 1.0
+			if config.Trials != nil {
+				trials = config.Trials[i]
+			}
+			outcome = m.Binomial(name, trials, m.Logistic(eta))
+		case PoissonLog:
+			outcome = m.Poisson(name, m.Exp(eta))
+		default:
+			log.Panicf("unknown family %d", config.Family)
+		}
+		if err := m.Observe(outcome, y[i]); err != nil {
+			log.Panicf("invalid outcome: %v", err)
+		}
+		if config.Weights != nil {
+			m.SetPower(outcome, config.Weights[i])
+		}
+	}
+
+	return glm
+}
+
+// OriginalScale converts the intercept and the coefficients in the trace of
+// a model fitted on standardized covariates to the original scale of the
+// covariates. The other variables are copied as is, and the trace is
+// returned unchanged when the covariates were not standardized.
+func (g *GLM) OriginalScale(samples trace.Trace) trace.Trace {
+	if g.means == nil {
+		return samples
+	}
+
+	converted := trace.Trace{}
+	for name, draws := range samples {
+		converted[name] = draws
+	}
+	intercept := append([]float64(nil), samples[g.Intercept.Name()]...)
+	for j, coefficient := range g.Coefficients {
+		draws, ok := samples[coefficient.Name()]
+		if !ok || len(draws) != len(intercept) {
+			log.Panicf("The trace is missing variable %s", coefficient.Name())
+		}
+		original := make([]float64, len(draws))
+		for s, beta := range draws {
+			original[s] = beta / g.stdDevs[j]
+			intercept[s] -= original[s] * g.means[j]
+		}
+		converted[coefficient.Name()] = original
+	}
+	converted[g.Intercept.Name()] = intercept
+
+	return converted
+}
+
+// checkLength panics if the optional per-observation values are not given
+// for each of the n observations.
+func checkLength(name string, values []float64, n int) {
+	if values != nil && len(values) != n {
+		log.Panicf("needed %d %s, got %d", n, name, len(values))
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/rlouf/gmc/trace"
+)
+
+// credibleMass is the probability mass of the intervals reported by the
+// tests.
+const credibleMass = 0.95
+
+// A BESTResult is the result of the comparison of the means of two groups.
+// The differences are those of the first group minus the second.
+type BESTResult struct {
+	Difference  Interval // difference of the means
+	EffectSize  Interval // difference of the means divided by the pooled standard deviation
+	ProbGreater float64  // posterior probability that the mean of the first group is greater
+	Trace       trace.Trace
+}
+
+// BEST compares the means of two groups, the Bayesian counterpart of the
+// two-sample t-test. The observations of each group follow a Student's t
+// distribution with its own mean and scale, and a common degree of freedom
+// so that outliers do not distort the estimates:
+//
+// "Bayesian estimation supersedes the t test" (Kruschke 2013)
+// https://doi.org/10.1037/a0029146
+//
+// The data are standardized with the pooled mean and standard deviation
+// before fitting; the priors are vague on this scale and the results are
+// reported on the original scale. The trace contains the standardized
+// parameters.
+func BEST(x, y []float64, numSamples int) BESTResult {
+	if len(x) < 2 || len(y) < 2 {
+		log.Panicf("needed at least 2 observations per group, got %d and %d", len(x), len(y))
+	}
+	center, scale := stat.MeanStdDev(append(append([]float64(nil), x...), y...), nil)
+
+	m := NewModel()
+	nuMinusOne := m.Exponential("nu_minus_one", m.Constant(1.0/29))
+	nu := m.Sum(nuMinusOne, m.Constant(1))
+	for g, group := range [][]float64{x, y} {
+		mu := m.Normal(fmt.Sprintf("mu_%d", g), m.Constant(0), m.Constant(10))
+		sigma := m.Uniform(fmt.Sprintf("sigma_%d", g), m.Constant(1e-3), m.Constant(1e3))
+		for i, value := range group {
+			observation := m.StudentT(fmt.Sprintf("y_%d_%d", g, i), nu, mu, sigma)
+			m.Observe(observation, (value-center)/scale)
+		}
+	}
+	samples := fitTest(m, []float64{29, 0, 1, 0, 1}, numSamples)
+
+	difference := make([]float64, len(samples["mu_0"]))
+	effectSize := make([]float64, len(difference))
+	var greater float64
+	for s := range difference {
+		d := samples["mu_0"][s] - samples["mu_1"][s]
+		s0, s1 := samples["sigma_0"][s], samples["sigma_1"][s]
+		difference[s] = scale * d
+		effectSize[s] = d / math.Sqrt((s0*s0+s1*s1)/2)
+		if d > 0 {
+			greater++
+		}
+	}
+
+	return BESTResult{
+		Difference:  credibleInterval(difference),
+		EffectSize:  credibleInterval(effectSize),
+		ProbGreater: greater / float64(len(difference)),
+		Trace:       samples,
+	}
+}
+
+// A CorrelationResult is the result of the estimation of the correlation
+// between two variables.
+type CorrelationResult struct {
+	Rho          Interval
+	ProbPositive float64 // posterior probability that the correlation is positive
+	Trace        trace.Trace
+}
+
+// Correlation estimates the correlation between paired observations x and y,
+// with a uniform prior on [-1, 1]. Both variables are standardized and the
+// pairs are modeled as bivariate normal through the conditional
+// distribution of y given x:
+//
+// y ~ Normal(rho * x, sqrt(1 - rho^2))
+//
+// Standardizing with the sample mean and standard deviation ignores their
+// uncertainty, which is negligible unless the sample is very small.
+func Correlation(x, y []float64, numSamples int) CorrelationResult {
+	if len(x) != len(y) {
+		log.Panicf("needed paired observations, got %d and %d", len(x), len(y))
+	}
+	if len(x) < 3 {
+		log.Panicf("needed at least 3 pairs, got %d", len(x))
+	}
+	xMean, xStd := stat.MeanStdDev(x, nil)
+	yMean, yStd := stat.MeanStdDev(y, nil)
+
+	m := NewModel()
+	rho := m.Uniform("rho", m.Constant(-1), m.Constant(1))
+	one, minusOne := m.Constant(1), m.Constant(-1)
+	sigma := m.Sqrt(m.Sum(one, m.Prod(minusOne, m.Prod(rho, rho))))
+	for i := range x {
+		zx := (x[i] - xMean) / xStd
+		observation := m.Normal(fmt.Sprintf("y_%d", i), m.Prod(rho, m.Constant(zx)), sigma)
+		m.Observe(observation, (y[i]-yMean)/yStd)
+	}
+	samples := fitTest(m, []float64{0}, numSamples)
+
+	var positive float64
+	for _, r := range samples["rho"] {
+		if r > 0 {
+			positive++
+		}
+	}
+
+	return CorrelationResult{
+		Rho:          credibleInterval(samples["rho"]),
+		ProbPositive: positive / float64(len(samples["rho"])),
+		Trace:        samples,
+	}
+}
+
+// A ProportionResult is the result of the comparison of the success
+// probabilities of two groups. The difference is that of the first group
+// minus the second.
+type ProportionResult struct {
+	Difference  Interval
+	ProbGreater float64 // posterior probability that the first group has the higher probability
+	Trace       trace.Trace
+}
+
+// Proportions compares the probability of success in two groups given the
+// number of successes and of trials in each, with uniform priors on the
+// probabilities.
+func Proportions(successes1, trials1, successes2, trials2 float64, numSamples int) ProportionResult {
+	m := NewModel()
+	for g, counts := range [][2]float64{{successes1, trials1}, {successes2, trials2}} {
+		if counts[1] < 1 || counts[0] < 0 || counts[0] > counts[1] {
+			log.Panicf("group %d needs between 0 and %f successes out of at least one trial, got %f", g, counts[1], counts[0])
+		}
+		theta := m.Beta(fmt.Sprintf("theta_%d", g), m.Constant(1), m.Constant(1))
+		m.Observe(m.Binomial(fmt.Sprintf("successes_%d", g), counts[1], theta), counts[0])
+	}
+	samples := fitTest(m, []float64{0.5, 0.5}, numSamples)
+
+	difference := make([]float64, len(samples["theta_0"]))
+	var greater float64
+	for s := range difference {
+		difference[s] = samples["theta_0"][s] - samples["theta_1"][s]
+		if difference[s] > 0 {
+			greater++
+		}
+	}
+
+	return ProportionResult{
+		Difference:  credibleInterval(difference),
+		ProbGreater: greater / float64(len(difference)),
+		Trace:       samples,
+	}
+}
+
+// fitTest samples the model of a test with the Metropolis-Hastings sampler.
+func fitTest(m *Model, initial []float64, numSamples int) trace.Trace {
+	if numSamples < 1 {
+		log.Panicf("needed at least one sample, got %d", numSamples)
+	}
+	sampler := NewMetropolisHastingsSampler(m)
+	return m.Sample(numSamples, initial, *sampler.MetropolisHastingser)
+}
+
+// credibleInterval returns the median and the central interval of the
+// samples that contains a proportion credibleMass of them.
+func credibleInterval(samples []float64) Interval {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	return Interval{
+		Lower:  stat.Quantile((1-credibleMass)/2, stat.Empirical, sorted, nil),
+		Median: stat.Quantile(0.5, stat.Empirical, sorted, nil),
+		Upper:  stat.Quantile((1+credibleMass)/2, stat.Empirical, sorted, nil),
+	}
+}
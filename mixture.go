@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"sort"
+)
+
+// RelabelByOrder undoes label switching in the trace of a mixture model by
+// imposing an ordering constraint after sampling.
+//
+// The likelihood of a mixture is invariant to a permutation of its
+// components, so the sampler can swap their labels from one draw to the
+// next and summaries of component-specific parameters become meaningless.
+// For each draw, RelabelByOrder sorts the components by increasing value of
+// one of their parameters and permutes all their parameters accordingly.
+//
+// `components` lists, for each component, the names of its parameters in
+// the same order, e.g. {{"mu_0", "sigma_0"}, {"mu_1", "sigma_1"}}; `key` is
+// the position of the parameter used to order the components. Variables
+// that do not belong to a component are copied as is.
+func RelabelByOrder(trace map[string][]float64, components [][]string, key int) map[string][]float64 {
+	if len(components) < 2 {
+		log.Panicf("a mixture needs at least 2 components to relabel, got %d", len(components))
+	}
+	numParams := len(components[0])
+	if key < 0 || key >= numParams {
+		log.Panicf("the key must be between 0 and %d, got %d", numParams-1, key)
+	}
+	numDraws := -1
+	for _, component := range components {
+		if len(component) != numParams {
+			log.Panicf("all components must have %d parameters, got %d", numParams, len(component))
+		}
+		for _, name := range component {
+			samples, ok := trace[name]
+			if !ok {
+				log.Panicf("The trace is missing variable %s", name)
+			}
+			if numDraws == -1 {
+				numDraws = len(samples)
+			} else if len(samples) != numDraws {
+				log.Panicf("variable %s has %d samples, expected %d", name, len(samples), numDraws)
+			}
+		}
+	}
+
+	relabeled := make(map[string][]float64)
+	for name, samples := range trace {
+		relabeled[name] = append([]float64(nil), samples...)
+	}
+
+	order := make([]int, len(components))
+	for i := 0; i < numDraws; i++ {
+		for k := range order {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return trace[components[order[a]][key]][i] < trace[components[order[b]][key]][i]
+		})
+		for k, source := range order {
+			for p := 0; p < numParams; p++ {
+				relabeled[components[k][p]][i] = trace[components[source][p]][i]
+			}
+		}
+	}
+
+	return relabeled
+}
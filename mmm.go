@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rlouf/gmc/node"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// A Channel is a marketing channel along with its spend at every period.
+type Channel struct {
+	Name  string
+	Spend []float64
+}
+
+// MarketingMixConfig sets the structure of a marketing mix model.
+type MarketingMixConfig struct {
+	MaxLag int     // number of periods over which the effect of spend carries over
+	Noise  float64 // standard deviation of the observation noise, in units of sales
+}
+
+// A MarketingMix is a model that relates the sales at each period to the
+// spend on marketing channels:
+//
+// sales(t) = scale * (baseline + sum_c coef_c * hill(adstock_c(t), halfSaturation_c, slope_c)) + noise
+//
+// The adstock gate models the carry-over of spend from one period to the
+// next and the Hill gate the diminishing returns of spend. `scale` is the
+// mean of the sales and the spend of each channel is divided by its mean, so
+// that the priors below are reasonable whatever the units of the data:
+//
+//	log_baseline, log_coef_c ~ Normal(0, 1)
+//	decay_c                  ~ Beta(2, 2)
+//	log_half_saturation_c    ~ Normal(0, 1)
+//	log_slope_c              ~ Normal(0, 0.5)
+//
+// The baseline, coefficients, half-saturation points and slopes are the
+// exponential of the corresponding variables so that the contribution of
+// every channel is positive. The variables of a channel are suffixed with
+// "_<name>", e.g. "decay_tv", and the observations are named "sales_<t>".
+//
+// "Bayesian Methods for Media Mix Modeling with Carryover and Shape Effects"
+// (Jin et al. 2017)
+// https://research.google/pubs/pub46001/
+type MarketingMix struct {
+	*Model
+	channels      []Channel
+	contributions [][]node.Var // contribution of each channel at each period
+	scale         float64
+}
+
+// NewMarketingMix builds the marketing mix model of the sales given the spend
+// on each channel over the same periods.
+func NewMarketingMix(sales []float64, channels []Channel, config MarketingMixConfig) *MarketingMix {
+	if len(channels) == 0 {
+		log.Panicf("the model needs at least one channel")
+	}
+	if config.MaxLag < 0 {
+		log.Panicf("The maximum lag must be positive, got %d", config.MaxLag)
+	}
+	if config.Noise <= 0 {
+		log.Panicf("The noise must be strictly positive, got %f", config.Noise)
+	}
+	scale := stat.Mean(sales, nil)
+	if scale <= 0 {
+		log.Panicf("the mean of the sales must be strictly positive, got %f", scale)
+	}
+
+	mmm := &MarketingMix{
+		Model:         NewModel(),
+		channels:      channels,
+		contributions: make([][]node.Var, len(channels)),
+		scale:         scale,
+	}
+	m := mmm.Model
+
+	zero, one := m.Constant(0), m.Constant(1)
+	baseline := m.Exp(m.Normal("log_baseline", zero, one))
+	for c, channel := range channels {
+		if len(channel.Spend) != len(sales) {
+			log.Panicf("channel %s has %d periods, expected %d", channel.Name, len(channel.Spend), len(sales))
+		}
+		spendScale := stat.Mean(channel.Spend, nil)
+		if spendScale <= 0 || floats.Min(channel.Spend) < 0 {
+			log.Panicf("the spend of channel %s must be positive and not all zero", channel.Name)
+		}
+		spend := make([]float64, len(channel.Spend))
+		floats.ScaleTo(spend, 1/spendScale, channel.Spend)
+
+		coef := m.Exp(m.Normal("log_coef_"+channel.Name, zero, one))
+		decay := m.Beta("decay_"+channel.Name, m.Constant(2), m.Constant(2))
+		halfSaturation := m.Exp(m.Normal("log_half_saturation_"+channel.Name, zero, one))
+		slope := m.Exp(m.Normal("log_slope_"+channel.Name, zero, m.Constant(0.5)))
+
+		mmm.contributions[c] = make([]node.Var, len(sales))
+		for t := range sales {
+			adstock := m.Adstock(spend, t, config.MaxLag, decay)
+			mmm.contributions[c][t] = m.Prod(coef, m.Hill(adstock, halfSaturation, slope))
+		}
+	}
+
+	noise := m.Constant(config.Noise)
+	for t, value := range sales {
+		mean := baseline
+		for c := range channels {
+			mean = m.Sum(mean, mmm.contributions[c][t])
+		}
+		y := m.Normal(fmt.Sprintf("sales_%d", t), m.Prod(m.Constant(scale), mean), noise)
+		m.Observe(y, value)
+	}
+
+	return mmm
+}
+
+// Contributions returns the posterior mean of the sales attributed to each
+// channel at every period, in the units of the sales.
+func (mmm *MarketingMix) Contributions(trace map[string][]float64) map[string][]float64 {
+	traceSize := mmm.traceSize(trace)
+
+	contributions := make(map[string][]float64)
+	for _, channel := range mmm.channels {
+		contributions[channel.Name] = make([]float64, len(mmm.contributions[0]))
+	}
+	for loc := 0; loc < traceSize; loc++ {
+		for _, variable := range mmm.stochastic {
+			variable.SetValue(trace[variable.Name()][loc])
+		}
+		for c, channel := range mmm.channels {
+			for t, contribution := range mmm.contributions[c] {
+				contributions[channel.Name][t] += mmm.scale * contribution.Value() / float64(traceSize)
+			}
+		}
+	}
+
+	return contributions
+}
@@ -1,13 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
 	"log"
 	"math"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/rlouf/gmc/monitor"
 	"github.com/rlouf/gmc/node"
+	"github.com/rlouf/gmc/rngsplit"
+	"github.com/rlouf/gmc/sampler"
+	"github.com/rlouf/gmc/trace"
 	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
-	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat"
 	"gonum.org/v1/gonum/stat/samplemv"
 )
 
@@ -27,14 +40,24 @@ import (
 // Bayesian inference on a PGM consists in infering the possible values for
 // the stochastic nodes given the value of the observed nodes and their
 // depencies as embedded in the graph.
+//
+// The stochastic variables are ordered by insertion: the order in which they
+// were added to the model, less those that were observed. This is the order
+// of the initial values and of the proposals passed to the samplers, and of
+// the columns of the matrices they return; VariableNames lists it.
 type Model struct {
 	deterministic []node.Var // contains constants and transformed variables
 	observed      []node.RandVar
 	stochastic    []node.RandVar
+	powers        map[string]float64 // likelihood temperature of observed variables
+	generated     []generatedQuantity
 
 	Src *rand.Rand
 }
 
+// Models can be used as a target by gmc's samplers as well as gonum's.
+var _ sampler.GradTarget = (*Model)(nil)
+
 // NewModel creates a new model with sensible defaults.
 func NewModel() *Model {
 	return &Model{
@@ -44,16 +67,96 @@ func NewModel() *Model {
 
 // Observe sets the value of a variable and moves the latter from the
 // stochastic set to the observed set.
-func (m *Model) Observe(variable node.RandVar, value float64) {
+//
+// It returns an error and leaves the model unchanged if the value is outside
+// the support of the variable's distribution: the likelihood would be zero
+// whatever the value of the parameters, and every proposal rejected.
+func (m *Model) Observe(variable node.RandVar, value float64) error {
 	for i, model_var := range m.stochastic {
 		if variable.Name() == model_var.Name() {
-			m.stochastic = append(m.stochastic[:i], m.stochastic[i+1:]...)
+			if err := model_var.SetValue(value); err != nil {
+				return fmt.Errorf("cannot observe %g for %s: %v", value, variable.Name(), err)
+			}
+			// Removing the variable in place would shift the variables
+			// of slices that share the array, e.g. a copy made to restore
+			// the model.
+			remaining := make([]node.RandVar, 0, len(m.stochastic)-1)
+			remaining = append(remaining, m.stochastic[:i]...)
+			m.stochastic = append(remaining, m.stochastic[i+1:]...)
 			m.observed = append(m.observed, model_var)
-			model_var.SetValue(value)
-			return
+			return nil
 		}
 	}
 	log.Panicf("the variable does not exist: %s", variable.Name())
+	return nil
+}
+
+// SetPower raises the likelihood of an observed variable to the power w,
+// i.e. multiplies its contribution to the model's log-probability by w.
+//
+// With 0 < w < 1 this down-weights the observation, which is how power priors
+// borrow information from historical data. Applying the same power to all the
+// observed variables yields a tempered posterior.
+func (m *Model) SetPower(variable node.RandVar, w float64) {
+	if w < 0 {
+		log.Panicf("the likelihood power must be >= 0, got %f", w)
+	}
+	for _, observed := range m.observed {
+		if observed.Name() == variable.Name() {
+			if m.powers == nil {
+				m.powers = make(map[string]float64)
+			}
+			m.powers[variable.Name()] = w
+			return
+		}
+	}
+	log.Panicf("the variable is not observed: %s", variable.Name())
+}
+
+// power returns the power the likelihood of an observed variable is
+// raised to. It defaults to 1.
+func (m *Model) power(variable node.RandVar) float64 {
+	if w, ok := m.powers[variable.Name()]; ok {
+		return w
+	}
+	return 1
+}
+
+// ConditionOn replaces the prior of the variables listed in `varNames` by
+// the posterior distribution obtained when fitting another model that shares
+// these variables. The `trace` must contain the samples of the other model.
+//
+// This allows to split inference in several stages by sequential prior
+// updating: the posterior of the first model, approximated by a kernel
+// density estimate of its samples, becomes the prior of the second model.
+// The shared variables are sampled with the other variables, so the data of
+// the second model still updates them.
+//
+// It is not a cut: to keep the data of the second model from influencing
+// the shared variables, fix them at draws of the first model's trace with
+// SampleConditional instead, and pool the resulting traces.
+func (m *Model) ConditionOn(other *Model, trace map[string][]float64, varNames []string) {
+	for _, name := range varNames {
+		if !other.IsTaken(name) {
+			log.Panicf("the variable does not exist in the other model: %s", name)
+		}
+		draws, ok := trace[name]
+		if !ok || len(draws) == 0 {
+			log.Panicf("The trace is missing variable %s", name)
+		}
+
+		found := false
+		for i, variable := range m.stochastic {
+			if variable.Name() == name {
+				m.stochastic[i] = node.NewEmpirical(variable, draws, m.Src)
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Panicf("the variable does not exist: %s", name)
+		}
+	}
 }
 
 // LogProb computes the log-probability of the graphical model given the
@@ -80,7 +183,9 @@ func (m *Model) LogProb(proposed []float64) float64 {
 		logprob += variable.LogProb()
 	}
 	for _, observed := range m.observed {
-		logprob += observed.LogProb()
+		if w := m.power(observed); w != 0 {
+			logprob += w * observed.LogProb()
+		}
 	}
 
 	return logprob
@@ -109,9 +214,7 @@ func (m *Model) SamplePriorPredictive(numSamples int) map[string][]float64 {
 	}
 
 	for i := 0; i < numSamples; i++ {
-		for _, v := range m.stochastic {
-			v.SetValue(v.Rand())
-		}
+		m.samplePrior()
 		for _, o := range m.observed {
 			name := o.Name()
 			samples[name][i] = o.Rand()
@@ -134,24 +237,293 @@ func (m *Model) SamplePriorPredictive(numSamples int) map[string][]float64 {
 // mechanism as in PyMC3 and Stan.
 // Ideally, all these would be optional. Maybe adding a Tune(Model) and Init(Model)
 // function at the sampler level is our best option?
-func (m *Model) Sample(nSamples int, initial []float64, sampler samplemv.MetropolisHastingser) map[string][]float64 {
+func (m *Model) Sample(nSamples int, initial []float64, sampler samplemv.MetropolisHastingser) trace.Trace {
+	return m.SampleWithOptions(nSamples, initial, sampler, SampleOptions{}).Trace
+}
+
+// SampleConditional generates samples from the posterior distribution of
+// the model's stochastic variables given that the variables in `fixed` take
+// the given values, e.g. to profile the posterior along some variables or to
+// debug a part of a model. The fixed variables are treated as observed for
+// the duration of the run, and the model is restored afterwards.
+//
+// `initial` contains the initial values of the other stochastic variables,
+// in the order of the model, and the proposal of the sampler must have their
+// dimension. The trace contains the fixed variables, with their value
+// repeated in every sample.
+func (m *Model) SampleConditional(fixed map[string]float64, nSamples int, initial []float64, sampler samplemv.MetropolisHastingser) trace.Trace {
+	stochastic := append([]node.RandVar(nil), m.stochastic...)
+	observed := append([]node.RandVar(nil), m.observed...)
+	defer func() {
+		m.stochastic, m.observed = stochastic, observed
+	}()
+
+	isStochastic := make(map[string]bool)
+	for _, variable := range stochastic {
+		isStochastic[variable.Name()] = true
+	}
+	for name := range fixed {
+		if !isStochastic[name] {
+			log.Panicf("the variable is not a stochastic variable of the model: %s", name)
+		}
+	}
+	// The variables are fixed in the order of the model rather than that of
+	// the map, so that runs are reproducible.
+	for _, variable := range stochastic {
+		if value, ok := fixed[variable.Name()]; ok {
+			if err := m.Observe(variable, value); err != nil {
+				log.Panicf("cannot fix %s: %v", variable.Name(), err)
+			}
+		}
+	}
+
+	samples := m.Sample(nSamples, initial, sampler)
+	for name, value := range fixed {
+		draws := make([]float64, nSamples)
+		for i := range draws {
+			draws[i] = value
+		}
+		samples[name] = draws
+	}
+	return samples
+}
+
+// SampleWithOptions generates samples from the posterior distribution of the
+// model like Sample, with the behaviour configured by `opts`. It returns the
+// trace along with statistics about the run and warnings about the quality
+// of the samples.
+//
+// The chain is run by chunks of at most `chunkSize` samples, each chunk
+// starting where the previous one stopped, so the memory used by the sampler
+// does not grow with the number of samples.
+func (m *Model) SampleWithOptions(nSamples int, initial []float64, sampler samplemv.MetropolisHastingser, opts SampleOptions) *SampleResult {
+	// There would be nothing to sample, and the size of a stored sample,
+	// which divides MaxTraceBytes, would be 0.
+	if len(m.stochastic) == 0 {
+		log.Panicf("the model has no stochastic variable to sample")
+	}
 	if len(initial) != len(m.stochastic) {
 		log.Panicf("needed %d initial points, got %d", len(m.stochastic), len(initial))
 	}
+	start := time.Now()
 	sampler.Initial = initial
 	sampler.Target = m
 
-	batch := mat.NewDense(nSamples, len(m.stochastic), nil)
-	sampler.Sample(batch)
-	trace := map[string][]float64{}
-	for i := 0; i < nSamples; i++ {
-		row := batch.RawRowView(i)
-		for j := 0; j < len(m.stochastic); j++ {
-			trace[m.stochastic[j].Name()] = append(trace[m.stochastic[j].Name()], row[j])
+	monitored := m.monitored(opts.Monitor)
+	capacity := nSamples
+	if opts.MaxTraceBytes > 0 {
+		// Each stored sample takes 8 bytes per variable.
+		capacity = opts.MaxTraceBytes / (8 * (len(monitored) + len(m.generated)))
+		if capacity < 2 {
+			log.Panicf("the trace needs at least %d bytes to store 2 samples, got %d", 16*(len(monitored)+len(m.generated)), opts.MaxTraceBytes)
+		}
+	}
+	if capacity > nSamples {
+		capacity = nSamples
+	}
+	// A run with a time budget rarely draws all the samples requested, so
+	// the trace grows as needed instead.
+	preallocated := capacity
+	if opts.MaxDuration > 0 && preallocated > chunkSize {
+		preallocated = chunkSize
+	}
+	samples := trace.Trace{}
+	for _, j := range monitored {
+		samples[m.stochastic[j].Name()] = make([]float64, 0, preallocated)
+	}
+
+	// The signal channel stays nil, and never receives, unless the run
+	// can be interrupted.
+	var interrupt chan os.Signal
+	if opts.StopOnInterrupt {
+		interrupt = make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		defer signal.Stop(interrupt)
+	}
+
+	// With a time budget the burn-in runs on its own so that it is not
+	// counted, and the chunks are shorter so that the run stops close to
+	// the deadline. They are as short when the run can be interrupted, so
+	// that it stops soon after the signal.
+	maxChunkSize := chunkSize
+	if opts.StopOnInterrupt {
+		maxChunkSize = timedChunkSize
+	}
+	var deadline time.Time
+	if opts.MaxDuration > 0 {
+		if sampler.BurnIn > 0 {
+			warmup := mat.NewDense(1, len(m.stochastic), nil)
+			sampler.Sample(warmup)
+			sampler.Initial = warmup.RawRowView(0)
+			sampler.BurnIn = 0
+		}
+		maxChunkSize = timedChunkSize
+		deadline = time.Now().Add(opts.MaxDuration)
+	}
+
+	// The disk trace receives the monitored variables then the generated
+	// quantities.
+	var disk *trace.DiskWriter
+	var diskRow []float64
+	var warnings []Warning
+	if opts.TracePath != "" {
+		var names []string
+		for _, j := range monitored {
+			names = append(names, m.stochastic[j].Name())
+		}
+		for _, g := range m.generated {
+			names = append(names, g.name)
+		}
+		var err error
+		if disk, err = trace.CreateDisk(opts.TracePath, m.spec(), names); err != nil {
+			warnings = append(warnings, Warning{TraceFileError, "", fmt.Sprintf("could not create the trace file: %v", err)})
+		}
+		diskRow = make([]float64, 0, len(names))
+	}
+
+	var moves, stored, drawn int
+	var interrupted, timedOut bool
+	thin := 1
+	var previous []float64
+	for remaining := nSamples; remaining > 0 && !interrupted && !timedOut; {
+		size := remaining
+		if size > maxChunkSize {
+			size = maxChunkSize
+		}
+		batch := mat.NewDense(size, len(m.stochastic), nil)
+		sampler.Sample(batch)
+		for i := 0; i < size; i++ {
+			row := batch.RawRowView(i)
+			if previous != nil && !floats.Equal(row, previous) {
+				moves++
+			}
+			previous = row
+			if opts.Progress != nil {
+				m.sendProgress(opts.Progress, nSamples-remaining+i, row, monitored)
+			}
+			if (nSamples-remaining+i)%thin != 0 {
+				continue
+			}
+			diskRow = diskRow[:0]
+			for _, j := range monitored {
+				name := m.stochastic[j].Name()
+				samples[name] = append(samples[name], row[j])
+				diskRow = append(diskRow, row[j])
+			}
+			if len(m.generated) > 0 {
+				m.setValues(row)
+				for _, g := range m.generated {
+					value := g.variable.Value()
+					samples[g.name] = append(samples[g.name], value)
+					diskRow = append(diskRow, value)
+				}
+			}
+			if disk != nil {
+				if err := disk.Append(diskRow); err != nil {
+					warnings = append(warnings, Warning{TraceFileError, "", fmt.Sprintf("stopped writing the trace file: %v", err)})
+					disk.Close()
+					disk = nil
+				}
+			}
+			stored++
+			if stored == capacity && nSamples-remaining+i < nSamples-1 {
+				samples = samples.Thin(2)
+				stored = len(samples[samples.Names()[0]])
+				thin *= 2
+			}
+		}
+
+		if disk != nil {
+			if err := disk.Flush(); err != nil {
+				warnings = append(warnings, Warning{TraceFileError, "", fmt.Sprintf("stopped writing the trace file: %v", err)})
+				disk.Close()
+				disk = nil
+			}
+		}
+
+		sampler.Initial = append([]float64(nil), batch.RawRowView(size-1)...)
+		sampler.BurnIn = 0
+		remaining -= size
+		drawn += size
+
+		select {
+		case <-interrupt:
+			// A second signal kills the process as usual if the rest of
+			// the run hangs.
+			signal.Stop(interrupt)
+			interrupted = true
+		default:
+		}
+		timedOut = opts.MaxDuration > 0 && time.Now().After(deadline)
+	}
+
+	result := &SampleResult{
+		Trace:       samples,
+		Thin:        thin,
+		Draws:       drawn,
+		Interrupted: interrupted,
+		Duration:    time.Since(start),
+		Warnings:    warnings,
+	}
+	if disk != nil {
+		if err := disk.Close(); err != nil {
+			result.Warnings = append(result.Warnings, Warning{TraceFileError, "", fmt.Sprintf("could not close the trace file: %v", err)})
+		}
+	}
+	if drawn > 1 {
+		result.AcceptanceRate = float64(moves) / float64(drawn-1)
+	}
+	result.diagnose()
+
+	return result
+}
+
+// sendProgress sends the monitored values of a sample on the channel
+// without blocking.
+func (m *Model) sendProgress(progress chan<- monitor.Draw, iteration int, row []float64, monitored []int) {
+	draw := monitor.Draw{Iteration: iteration, Values: make(map[string]float64)}
+	for _, j := range monitored {
+		draw.Values[m.stochastic[j].Name()] = row[j]
+	}
+	select {
+	case progress <- draw:
+	default:
+	}
+}
+
+// setValues sets the stochastic variables to the values of a sample.
+func (m *Model) setValues(values []float64) {
+	for j, variable := range m.stochastic {
+		variable.SetValue(values[j])
+	}
+}
+
+// monitored returns the position of the monitored variables among the
+// stochastic variables. All the variables are monitored when no name is
+// given.
+func (m *Model) monitored(names []string) []int {
+	var positions []int
+	if len(names) == 0 {
+		for j := range m.stochastic {
+			positions = append(positions, j)
 		}
+		return positions
 	}
 
-	return trace
+	for _, name := range names {
+		found := false
+		for j, variable := range m.stochastic {
+			if variable.Name() == name {
+				positions = append(positions, j)
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Panicf("cannot monitor %s: the variable does not exist or is observed", name)
+		}
+	}
+	return positions
 }
 
 // PosteriorPredictiveSample generates synthetic values for the observed variables using
@@ -163,37 +535,145 @@ func (m *Model) Sample(nSamples int, initial []float64, sampler samplemv.Metropo
 //
 // It returns a map from the observed variables' names to a slice of samples.
 func (m *Model) SamplePosteriorPredictive(numSamples int, trace map[string][]float64) map[string][]float64 {
+	return m.SamplePosteriorPredictiveWithOptions(numSamples, trace, PredictiveOptions{})
+}
 
-	traceSize := 0. // dirty. Include trace size in Trace object
-	for _, variable := range m.stochastic {
-		if _, ok := trace[variable.Name()]; !ok {
-			log.Panicf("The trace is missing variable %s", variable.Name())
-		}
-		traceSize = float64(len(trace[variable.Name()]))
-	}
+// SamplePosteriorPredictiveWithOptions generates synthetic values for the
+// observed variables like SamplePosteriorPredictive, with the selection of
+// the posterior samples and the parallelism configured by `opts`.
+func (m *Model) SamplePosteriorPredictiveWithOptions(numSamples int, trace map[string][]float64, opts PredictiveOptions) map[string][]float64 {
+	traceSize := m.traceSize(trace)
 
 	samples := make(map[string][]float64)
 	for _, o := range m.observed {
 		samples[o.Name()] = make([]float64, numSamples, numSamples)
 	}
 
-	// We choose one sample from the posterior distribution, set the values
-	// of variables and then generate a sample the observed variables
-	sampler := distuv.Uniform{Min: 0, Max: traceSize - 1, Src: m.Src}
-	var name string
-	for i := 0; i < numSamples; i++ {
-		loc := int(math.Round(sampler.Rand()))
+	// The posterior samples are chosen up front, from the model's source,
+	// so that they do not depend on how the simulation is split.
+	locs := make([]int, numSamples)
+	offset := m.Src.Float64()
+	for i := range locs {
+		if opts.Systematic {
+			locs[i] = int((float64(i) + offset) * float64(traceSize) / float64(numSamples))
+		} else {
+			locs[i] = m.Src.Intn(traceSize)
+		}
+	}
+
+	if opts.Build == nil {
+		m.simulatePredictive(trace, locs, 0, numSamples, samples)
+		return samples
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers == 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	numChunks := (numSamples + predictiveChunkSize - 1) / predictiveChunkSize
+	chunks := make(chan int, numChunks)
+	for c := 0; c < numChunks; c++ {
+		chunks <- c
+	}
+	close(chunks)
+
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers && w < numChunks; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := opts.Build()
+			worker.checkSameVariables(m)
+			for c := range chunks {
+				// The nodes share the model's source, so reseeding it
+				// changes the stream of the whole graph.
+				worker.Src.Seed(rngsplit.Derive(opts.Seed, uint64(c)))
+				start := c * predictiveChunkSize
+				end := start + predictiveChunkSize
+				if end > numSamples {
+					end = numSamples
+				}
+				// Each sample is written by a single worker.
+				worker.simulatePredictive(trace, locs, start, end, samples)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return samples
+}
+
+// simulatePredictive sets the stochastic variables to the posterior samples
+// at locs[start:end] and stores a draw of the observed variables for each
+// in the samples start to end-1.
+func (m *Model) simulatePredictive(trace map[string][]float64, locs []int, start, end int, samples map[string][]float64) {
+	for i := start; i < end; i++ {
 		for _, variable := range m.stochastic {
-			name = variable.Name()
-			variable.SetValue(trace[name][loc])
+			variable.SetValue(trace[variable.Name()][locs[i]])
 		}
 		for _, observed := range m.observed {
-			name = observed.Name()
-			samples[name][i] = observed.Rand()
+			samples[observed.Name()][i] = observed.Rand()
 		}
 	}
+}
 
-	return samples
+// checkSameVariables panics if the model does not have the same stochastic
+// and observed variables as the other model, in the same order.
+func (m *Model) checkSameVariables(other *Model) {
+	same := func(a, b []node.RandVar) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i].Name() != b[i].Name() {
+				return false
+			}
+		}
+		return true
+	}
+	if !same(m.stochastic, other.stochastic) || !same(m.observed, other.observed) {
+		log.Panicf("the built model does not have the same variables as the model")
+	}
+}
+
+// InitialFromTrace returns initial values for the samplers taken from a
+// previous posterior: the median of the samples of each stochastic
+// variable, in the order of the model. Starting there rather than from an
+// arbitrary point shortens the burn-in when a model is refitted on updated
+// data.
+func (m *Model) InitialFromTrace(trace map[string][]float64) []float64 {
+	initial := make([]float64, len(m.stochastic))
+	for i, variable := range m.stochastic {
+		samples, ok := trace[variable.Name()]
+		if !ok || len(samples) == 0 {
+			log.Panicf("The trace is missing variable %s", variable.Name())
+		}
+		sorted := append([]float64(nil), samples...)
+		sort.Float64s(sorted)
+		initial[i] = stat.Quantile(0.5, stat.Empirical, sorted, nil)
+	}
+	return initial
+}
+
+// traceSize returns the number of samples of the stochastic variables in
+// the trace, and panics if some are missing.
+func (m *Model) traceSize(trace map[string][]float64) int {
+	traceSize := -1
+	for _, variable := range m.stochastic {
+		samples, ok := trace[variable.Name()]
+		if !ok {
+			log.Panicf("The trace is missing variable %s", variable.Name())
+		}
+		if traceSize == -1 {
+			traceSize = len(samples)
+		} else if len(samples) != traceSize {
+			log.Panicf("variable %s has %d samples, expected %d", variable.Name(), len(samples), traceSize)
+		}
+	}
+	if traceSize < 1 {
+		log.Panicf("the trace is empty")
+	}
+	return traceSize
 }
 
 // Normal adds a stochastic variable whose value is normally
@@ -207,6 +687,27 @@ func (m *Model) Normal(name string, mu, sigma node.Var) *node.Normal {
 	return newNormal
 }
 
+// NormalObservations adds to the model a normally distributed variable for
+// each value, with mean `mu` and the corresponding standard deviation in
+// `sigmas`, and observes it. This describes data whose measurement errors are
+// known and differ from one point to the next.
+//
+// The variables are named "<name>_<i>" after the position of the value.
+func (m *Model) NormalObservations(name string, mu node.Var, values, sigmas []float64) []*node.Normal {
+	if len(values) != len(sigmas) {
+		log.Panicf("needed %d standard deviations, got %d", len(values), len(sigmas))
+	}
+	observations := make([]*node.Normal, len(values))
+	for i, value := range values {
+		if sigmas[i] <= 0 {
+			log.Panicf("The standard deviation must be strictly positive, got %f", sigmas[i])
+		}
+		observations[i] = m.Normal(fmt.Sprintf("%s_%d", name, i), mu, m.Constant(sigmas[i]))
+		m.Observe(observations[i], value)
+	}
+	return observations
+}
+
 // Beta adds a stochastic variable whose value follows a Beta
 // distribution to the model. Returns a pointer to this variable.
 func (m *Model) Beta(name string, alpha, beta node.Var) *node.Beta {
@@ -243,6 +744,558 @@ func (m *Model) Binomial(name string, N float64, p node.Var) *node.Binomial {
 	return newBinomial
 }
 
+// Huber adds a stochastic variable that follows a Huberized normal
+// distribution to the model. Used as a likelihood it yields a regression
+// that is robust to outliers. Returns a pointer to this variable.
+func (m *Model) Huber(name string, mu, sigma node.Var, delta float64) *node.Huber {
+	if delta <= 0 {
+		log.Panicf("The Huber threshold must be > 0, got %f", delta)
+	}
+	newHuber := node.NewHuber(name, mu, sigma, delta, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newHuber)
+	return newHuber
+}
+
+// AsymmetricLaplace adds a stochastic variable that follows an asymmetric
+// Laplace distribution to the model. Used as a likelihood it yields a
+// quantile regression for the quantile tau. Returns a pointer to this
+// variable.
+func (m *Model) AsymmetricLaplace(name string, mu, sigma node.Var, tau float64) *node.AsymmetricLaplace {
+	if tau <= 0 || tau >= 1 {
+		log.Panicf("The quantile must be in (0,1), got %f", tau)
+	}
+	newAsymmetricLaplace := node.NewAsymmetricLaplace(name, mu, sigma, tau, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newAsymmetricLaplace)
+	return newAsymmetricLaplace
+}
+
+// Gamma adds a stochastic variable that follows a Gamma distribution of
+// shape alpha and rate beta to the model. Returns a pointer to this variable.
+func (m *Model) Gamma(name string, alpha, beta node.Var) *node.Gamma {
+	newGamma := node.NewGamma(name, alpha, beta, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newGamma)
+	return newGamma
+}
+
+// Dirichlet adds a random probability vector that follows a Dirichlet
+// distribution of concentration parameters `alphas` to the model. Returns a
+// pointer to this vector, whose components can be used as the parameters of
+// other nodes.
+//
+// The vector is represented by one Gamma stochastic variable per component,
+// see node.Dirichlet. The components are stored in the trace as generated
+// quantities named "<name>_<k>".
+func (m *Model) Dirichlet(name string, alphas []node.Var) *node.Dirichlet {
+	if len(alphas) < 2 {
+		log.Panicf("The Dirichlet distribution needs at least 2 components, got %d", len(alphas))
+	}
+	newDirichlet := node.NewDirichlet(name, alphas, m.Src)
+	for _, g := range newDirichlet.Gammas {
+		if m.IsTaken(g.Name()) {
+			log.Panicf("variable name is already taken: %s", g.Name())
+		}
+		m.stochastic = append(m.stochastic, g)
+	}
+	for k, component := range newDirichlet.Components() {
+		m.Generated(fmt.Sprintf("%s_%d", name, k), component)
+	}
+	return newDirichlet
+}
+
+// Categorical adds a stochastic variable that takes the value k with
+// probability p[k] to the model. The probabilities can be the components of
+// a Dirichlet vector. Returns a pointer to this variable.
+func (m *Model) Categorical(name string, p []node.Var) *node.Categorical {
+	if len(p) < 2 {
+		log.Panicf("The categorical distribution needs at least 2 categories, got %d", len(p))
+	}
+	newCategorical := node.NewCategorical(name, p, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newCategorical)
+	return newCategorical
+}
+
+// Poisson adds a stochastic variable that follows a Poisson distribution of
+// rate lambda to the model. Returns a pointer to this variable.
+func (m *Model) Poisson(name string, lambda node.Var) *node.Poisson {
+	newPoisson := node.NewPoisson(name, lambda, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newPoisson)
+	return newPoisson
+}
+
+// Exponential adds a stochastic variable that follows an exponential
+// distribution of the given rate to the model. Returns a pointer to this
+// variable.
+func (m *Model) Exponential(name string, rate node.Var) *node.Exponential {
+	newExponential := node.NewExponential(name, rate, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newExponential)
+	return newExponential
+}
+
+// Uniform adds a stochastic variable that is uniformly distributed between
+// min and max to the model. Returns a pointer to this variable.
+func (m *Model) Uniform(name string, min, max node.Var) *node.Uniform {
+	newUniform := node.NewUniform(name, min, max, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newUniform)
+	return newUniform
+}
+
+// StudentT adds a stochastic variable that follows a Student's t
+// distribution with nu degrees of freedom to the model. Used as a likelihood
+// it yields a regression that is robust to outliers. Returns a pointer to
+// this variable.
+func (m *Model) StudentT(name string, nu, mu, sigma node.Var) *node.StudentT {
+	newStudentT := node.NewStudentT(name, nu, mu, sigma, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newStudentT)
+	return newStudentT
+}
+
+// HalfNormal adds a stochastic variable that follows a half-normal
+// distribution of scale sigma to the model, typically as the prior of a
+// standard deviation. Returns a pointer to this variable.
+func (m *Model) HalfNormal(name string, sigma node.Var) *node.HalfNormal {
+	newHalfNormal := node.NewHalfNormal(name, sigma, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newHalfNormal)
+	return newHalfNormal
+}
+
+// HalfCauchy adds a stochastic variable that follows a half-Cauchy
+// distribution of the given scale to the model, typically as the prior of a
+// standard deviation. Returns a pointer to this variable.
+func (m *Model) HalfCauchy(name string, scale node.Var) *node.HalfCauchy {
+	newHalfCauchy := node.NewHalfCauchy(name, scale, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newHalfCauchy)
+	return newHalfCauchy
+}
+
+// LogNormal adds a stochastic variable whose logarithm is normally
+// distributed to the model. Returns a pointer to this variable.
+func (m *Model) LogNormal(name string, mu, sigma node.Var) *node.LogNormal {
+	newLogNormal := node.NewLogNormal(name, mu, sigma, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newLogNormal)
+	return newLogNormal
+}
+
+// Cauchy adds a stochastic variable that follows a Cauchy distribution to
+// the model. Returns a pointer to this variable.
+func (m *Model) Cauchy(name string, mu, scale node.Var) *node.Cauchy {
+	newCauchy := node.NewCauchy(name, mu, scale, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newCauchy)
+	return newCauchy
+}
+
+// Laplace adds a stochastic variable that follows a Laplace distribution to
+// the model. Used as the prior of regression coefficients it shrinks them
+// towards mu like an L1 penalty. Returns a pointer to this variable.
+func (m *Model) Laplace(name string, mu, scale node.Var) *node.Laplace {
+	newLaplace := node.NewLaplace(name, mu, scale, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newLaplace)
+	return newLaplace
+}
+
+// Weibull adds a stochastic variable that follows a Weibull distribution of
+// shape k and scale lambda to the model. Returns a pointer to this variable.
+func (m *Model) Weibull(name string, k, lambda node.Var) *node.Weibull {
+	newWeibull := node.NewWeibull(name, k, lambda, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newWeibull)
+	return newWeibull
+}
+
+// InverseGamma adds a stochastic variable whose inverse follows a Gamma
+// distribution of shape alpha and rate beta to the model, typically as the
+// prior of a variance. Returns a pointer to this variable.
+func (m *Model) InverseGamma(name string, alpha, beta node.Var) *node.InverseGamma {
+	newInverseGamma := node.NewInverseGamma(name, alpha, beta, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newInverseGamma)
+	return newInverseGamma
+}
+
+// ChiSquared adds a stochastic variable that follows a chi-squared
+// distribution with k degrees of freedom to the model. Returns a pointer to
+// this variable.
+func (m *Model) ChiSquared(name string, k node.Var) *node.ChiSquared {
+	newChiSquared := node.NewChiSquared(name, k, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newChiSquared)
+	return newChiSquared
+}
+
+// Pareto adds a stochastic variable that follows a Pareto distribution of
+// minimum value xm and shape alpha to the model. Returns a pointer to this
+// variable.
+func (m *Model) Pareto(name string, xm, alpha node.Var) *node.Pareto {
+	newPareto := node.NewPareto(name, xm, alpha, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newPareto)
+	return newPareto
+}
+
+// NormalMixture adds a stochastic variable that follows a mixture of normal
+// distributions of weights, means mus and standard deviations sigmas to the
+// model. The weights can be the components of a Dirichlet vector. Returns a
+// pointer to this variable.
+func (m *Model) NormalMixture(name string, weights, mus, sigmas []node.Var) *node.NormalMixture {
+	if len(mus) != len(weights) || len(sigmas) != len(weights) {
+		log.Panicf("needed as many means and standard deviations as weights (%d), got %d and %d", len(weights), len(mus), len(sigmas))
+	}
+	newNormalMixture := node.NewNormalMixture(name, weights, mus, sigmas, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newNormalMixture)
+	return newNormalMixture
+}
+
+// NegativeBinomial adds a stochastic variable that follows a negative
+// binomial distribution of mean mu and dispersion alpha to the model; its
+// variance is mu + mu^2 / alpha. Use it in place of a Poisson variable when
+// the counts are over-dispersed. Returns a pointer to this variable.
+func (m *Model) NegativeBinomial(name string, mu, alpha node.Var) *node.NegativeBinomial {
+	newNegativeBinomial := node.NewNegativeBinomialMean(name, mu, alpha, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.deterministic = append(m.deterministic, newNegativeBinomial.P)
+	m.stochastic = append(m.stochastic, newNegativeBinomial)
+	return newNegativeBinomial
+}
+
+// NegativeBinomialRP adds a stochastic variable that counts the failures
+// before the r-th success in trials that succeed with probability p to the
+// model. Returns a pointer to this variable.
+func (m *Model) NegativeBinomialRP(name string, r, p node.Var) *node.NegativeBinomial {
+	newNegativeBinomial := node.NewNegativeBinomial(name, r, p, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newNegativeBinomial)
+	return newNegativeBinomial
+}
+
+// Geometric adds a stochastic variable that counts the failures before the
+// first success in trials that succeed with probability p to the model.
+// Returns a pointer to this variable.
+func (m *Model) Geometric(name string, p node.Var) *node.Geometric {
+	newGeometric := node.NewGeometric(name, p, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newGeometric)
+	return newGeometric
+}
+
+// DiscreteUniform adds a stochastic variable that takes each of the integers
+// between min and max, included, with the same probability to the model, e.g.
+// the year of a changepoint:
+//
+//	tau := m.DiscreteUniform("tau", m.Constant(1851), m.Constant(1962))
+//	rate := m.Switch(0, m.Sum(m.Constant(year), m.Prod(tau, m.Constant(-1))), early, late)
+//
+// Returns a pointer to this variable.
+func (m *Model) DiscreteUniform(name string, min, max node.Var) *node.DiscreteUniform {
+	newDiscreteUniform := node.NewDiscreteUniform(name, min, max, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newDiscreteUniform)
+	return newDiscreteUniform
+}
+
+// BetaBinomial adds a stochastic variable that counts the successes among N
+// trials whose probability of success follows a Beta distribution of
+// parameters alpha and beta to the model. Returns a pointer to this
+// variable.
+func (m *Model) BetaBinomial(name string, N float64, alpha, beta node.Var) *node.BetaBinomial {
+	if N <= 0 {
+		log.Panicf("The number of bernoulli trial must be > 0, got %f", N)
+	}
+	newBetaBinomial := node.NewBetaBinomial(name, N, alpha, beta, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newBetaBinomial)
+	return newBetaBinomial
+}
+
+// Variable adds a stochastic variable of a kind registered with
+// node.Register to the model, which lets models use distributions provided
+// by other packages. The parameters are passed to the factory of the kind
+// in order. Returns this variable.
+func (m *Model) Variable(kind, name string, params ...node.Var) node.RandVar {
+	factory, ok := node.Lookup(kind)
+	if !ok {
+		log.Panicf("unknown kind of variable %s, registered kinds are %v", kind, node.Kinds())
+	}
+	newVariable, err := factory(name, params, m.Src)
+	if err != nil {
+		log.Panicf("cannot build %s ~ %s: %v", name, kind, err)
+	}
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newVariable)
+	return newVariable
+}
+
+// Multinomial adds a vector of counts of N trials in categories of
+// probabilities p to the model. The probabilities can be the components of
+// a Dirichlet vector. Returns a pointer to this vector.
+//
+// The count of each category is a variable named "<name>_<k>", see
+// node.Multinomial. The counts are meant to be observed:
+//
+//	counts := m.Multinomial("counts", 20, p)
+//	for k, c := range counts.Components {
+//		m.Observe(c, observed[k])
+//	}
+func (m *Model) Multinomial(name string, N float64, p []node.Var) *node.Multinomial {
+	if N <= 0 {
+		log.Panicf("The number of trials must be > 0, got %f", N)
+	}
+	if len(p) < 2 {
+		log.Panicf("The multinomial distribution needs at least 2 categories, got %d", len(p))
+	}
+	newMultinomial := node.NewMultinomial(name, N, p, m.Src)
+	for _, c := range newMultinomial.Components {
+		if m.IsTaken(c.Name()) {
+			log.Panicf("variable name is already taken: %s", c.Name())
+		}
+		m.stochastic = append(m.stochastic, c)
+	}
+	return newMultinomial
+}
+
+// Wishart adds a symmetric positive definite matrix that follows a Wishart
+// distribution of scale matrix `scale` and `dof` degrees of freedom to the
+// model. Its entries can be used as the parameters of other nodes, see
+// node.Wishart.Entry. Returns a pointer to this matrix.
+//
+// The matrix is represented by scalar stochastic variables with the Bartlett
+// decomposition, see node.Wishart. The entries of its lower triangle are
+// stored in the trace as generated quantities named "<name>_<i>_<j>".
+func (m *Model) Wishart(name string, scale *mat.SymDense, dof float64) *node.Wishart {
+	return m.addBartlett(node.NewWishart(name, scale, dof, m.Src))
+}
+
+// InverseWishart adds a symmetric positive definite matrix that follows an
+// inverse-Wishart distribution of scale matrix `scale` and `dof` degrees of
+// freedom to the model, typically the prior of a covariance matrix. Returns
+// a pointer to this matrix. See Model.Wishart.
+func (m *Model) InverseWishart(name string, scale *mat.SymDense, dof float64) *node.Wishart {
+	return m.addBartlett(node.NewInverseWishart(name, scale, dof, m.Src))
+}
+
+func (m *Model) addBartlett(w *node.Wishart) *node.Wishart {
+	for _, variable := range w.Variables() {
+		if m.IsTaken(variable.Name()) {
+			log.Panicf("variable name is already taken: %s", variable.Name())
+		}
+		m.stochastic = append(m.stochastic, variable)
+	}
+	for i := 0; i < w.Dim(); i++ {
+		for j := 0; j <= i; j++ {
+			m.Generated(fmt.Sprintf("%s_%d_%d", w.Name(), i, j), w.Entry(i, j))
+		}
+	}
+	return w
+}
+
+// LKJCorr adds a dim×dim correlation matrix that follows an LKJ
+// distribution of shape eta to the model. Its entries, and those of its
+// Cholesky factor, can be used as the parameters of other nodes. Returns a
+// pointer to this matrix.
+//
+// The matrix is represented by one Beta stochastic variable per partial
+// correlation, see node.LKJCorr. The correlations below the diagonal are
+// stored in the trace as generated quantities named "<name>_<i>_<j>".
+func (m *Model) LKJCorr(name string, dim int, eta float64) *node.LKJCorr {
+	newLKJCorr := node.NewLKJCorr(name, dim, eta, m.Src)
+	for _, b := range newLKJCorr.Betas {
+		if m.IsTaken(b.Name()) {
+			log.Panicf("variable name is already taken: %s", b.Name())
+		}
+		m.stochastic = append(m.stochastic, b)
+	}
+	for i := 1; i < dim; i++ {
+		for j := 0; j < i; j++ {
+			m.Generated(fmt.Sprintf("%s_%d_%d", name, i, j), newLKJCorr.Entry(i, j))
+		}
+	}
+	return newLKJCorr
+}
+
+// DirichletMultinomial adds a vector of counts of N trials in categories
+// whose probabilities follow a Dirichlet distribution of concentrations
+// alpha to the model. The probabilities are integrated out. Returns a
+// pointer to this vector.
+//
+// The count of each category is a variable named "<name>_<k>", see
+// node.DirichletMultinomial. The counts are meant to be observed, as those
+// of Model.Multinomial.
+func (m *Model) DirichletMultinomial(name string, N float64, alpha []node.Var) *node.DirichletMultinomial {
+	if N <= 0 {
+		log.Panicf("The number of trials must be > 0, got %f", N)
+	}
+	if len(alpha) < 2 {
+		log.Panicf("The Dirichlet-multinomial distribution needs at least 2 categories, got %d", len(alpha))
+	}
+	newDirichletMultinomial := node.NewDirichletMultinomial(name, N, alpha, m.Src)
+	for _, c := range newDirichletMultinomial.Components {
+		if m.IsTaken(c.Name()) {
+			log.Panicf("variable name is already taken: %s", c.Name())
+		}
+		m.stochastic = append(m.stochastic, c)
+	}
+	return newDirichletMultinomial
+}
+
+// ZeroInflatedPoisson adds a stochastic variable that is 0 with probability
+// pi and otherwise follows a Poisson distribution of rate lambda to the
+// model. Returns a pointer to this variable.
+func (m *Model) ZeroInflatedPoisson(name string, pi, lambda node.Var) *node.ZeroInflatedPoisson {
+	newZeroInflatedPoisson := node.NewZeroInflatedPoisson(name, pi, lambda, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newZeroInflatedPoisson)
+	return newZeroInflatedPoisson
+}
+
+// ZeroInflatedNegBinomial adds a stochastic variable that is 0 with
+// probability pi and otherwise follows a negative binomial distribution of
+// mean mu and dispersion alpha to the model. Returns a pointer to this
+// variable.
+func (m *Model) ZeroInflatedNegBinomial(name string, pi, mu, alpha node.Var) *node.ZeroInflatedNegBinomial {
+	newZeroInflatedNegBinomial := node.NewZeroInflatedNegBinomial(name, pi, mu, alpha, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newZeroInflatedNegBinomial)
+	return newZeroInflatedNegBinomial
+}
+
+// Mixture adds a stochastic variable drawn from one of the components with
+// probability weights[k] to the model. The component is marginalized out,
+// see node.Mixture. The components are built with the node constructors and
+// are not added to the model, e.g.:
+//
+//	m.Mixture("y", p.Components(),
+//		node.NewNormal("y_0", mu0, sigma, m.Src),
+//		node.NewNormal("y_1", mu1, sigma, m.Src))
+//
+// Returns a pointer to this variable.
+func (m *Model) Mixture(name string, weights []node.Var, components ...node.RandVar) *node.Mixture {
+	if len(components) < 2 {
+		log.Panicf("The mixture needs at least 2 components, got %d", len(components))
+	}
+	if len(weights) != len(components) {
+		log.Panicf("needed one weight per component (%d), got %d", len(components), len(weights))
+	}
+	newMixture := node.NewMixture(name, weights, components, m.Src)
+	if m.IsTaken(name) {
+		log.Panicf("variable name is already taken: %s", name)
+	}
+	m.stochastic = append(m.stochastic, newMixture)
+	return newMixture
+}
+
+// Truncated adds a stochastic variable restricted to the interval
+// (lower, upper] of the support of dist to the model, see node.Truncated.
+// dist is built with the node constructors, is not added to the model and
+// gives its name to the variable, e.g.:
+//
+//	m.Truncated(node.NewNormal("height", mu, sigma, m.Src), 0, math.Inf(1))
+//
+// Returns a pointer to this variable.
+func (m *Model) Truncated(dist node.RandVar, lower, upper float64) *node.Truncated {
+	newTruncated := node.Truncate(dist, lower, upper)
+	if m.IsTaken(dist.Name()) {
+		log.Panicf("variable name is already taken: %s", dist.Name())
+	}
+	m.stochastic = append(m.stochastic, newTruncated)
+	return newTruncated
+}
+
+// Censored adds an observation of dist that is censored outside of
+// [lower, upper] to the model, see node.Censored: a value at or below lower
+// only tells that the variable is below lower, and likewise above upper. dist
+// is built with the node constructors, is not added to the model and gives
+// its name to the variable, e.g. for a survival time observed until the end
+// of the study:
+//
+//	t := m.Censored(node.NewWeibull("t_3", k, lambda, m.Src), math.Inf(-1), 365)
+//	m.Observe(t, 365) // still alive
+//
+// Returns a pointer to this variable.
+func (m *Model) Censored(dist node.RandVar, lower, upper float64) *node.Censored {
+	newCensored := node.NewCensored(dist, lower, upper)
+	if m.IsTaken(dist.Name()) {
+		log.Panicf("variable name is already taken: %s", dist.Name())
+	}
+	m.stochastic = append(m.stochastic, newCensored)
+	return newCensored
+}
+
+// IntervalCensored adds an observation of dist that is only known to be in
+// (lower, upper] to the model, see node.Censored. Returns a pointer to this
+// variable.
+func (m *Model) IntervalCensored(dist node.RandVar, lower, upper float64) *node.Censored {
+	newCensored := node.NewIntervalCensored(dist, lower, upper)
+	if m.IsTaken(dist.Name()) {
+		log.Panicf("variable name is already taken: %s", dist.Name())
+	}
+	m.stochastic = append(m.stochastic, newCensored)
+	return newCensored
+}
+
 // Constant adds a deterministic variable that has a constant value.
 func (m *Model) Constant(value float64) node.Var {
 	newConst := node.NewConstant(value)
@@ -303,6 +1356,68 @@ func (m *Model) Switch(threshold float64, Switch, Left, Right node.Var) node.Var
 	return transformed
 }
 
+// Exp adds to the model a deterministic node the value of which is the
+// exponential of the value of the input node.
+func (m *Model) Exp(x node.Var) node.Var {
+	transformed := &node.ExpGate{
+		X: x,
+	}
+	m.deterministic = append(m.deterministic, transformed)
+	return transformed
+}
+
+// Sqrt adds to the model a deterministic node the value of which is the
+// square root of the value of the input node.
+func (m *Model) Sqrt(x node.Var) node.Var {
+	transformed := &node.SqrtGate{
+		X: x,
+	}
+	m.deterministic = append(m.deterministic, transformed)
+	return transformed
+}
+
+// Adstock adds to the model a deterministic node the value of which is the
+// geometrically decayed sum of the values of `series` up to time t.
+func (m *Model) Adstock(series []float64, t, maxLag int, decay node.Var) node.Var {
+	if t < 0 || t >= len(series) {
+		log.Panicf("the time must be between 0 and %d, got %d", len(series)-1, t)
+	}
+	if maxLag < 0 {
+		log.Panicf("The maximum lag must be positive, got %d", maxLag)
+	}
+	transformed := &node.AdstockGate{
+		Series: series,
+		T:      t,
+		MaxLag: maxLag,
+		Decay:  decay,
+	}
+	m.deterministic = append(m.deterministic, transformed)
+	return transformed
+}
+
+// Hill adds to the model a deterministic node the value of which is the
+// Hill saturation function of the value of the input node.
+func (m *Model) Hill(x, halfSaturation, slope node.Var) node.Var {
+	transformed := &node.HillGate{
+		X:              x,
+		HalfSaturation: halfSaturation,
+		Slope:          slope,
+	}
+	m.deterministic = append(m.deterministic, transformed)
+	return transformed
+}
+
+// VariableNames returns the names of the stochastic variables in the order
+// of the model: the order of the initial values and of the proposals passed
+// to the samplers.
+func (m *Model) VariableNames() []string {
+	names := make([]string, len(m.stochastic))
+	for i, variable := range m.stochastic {
+		names[i] = variable.Name()
+	}
+	return names
+}
+
 // IsTaken returns `true` is the name passed as an input has already
 // been taken by a node in the graph.
 func (m *Model) IsTaken(name string) bool {
@@ -316,5 +1431,24 @@ func (m *Model) IsTaken(name string) bool {
 			return true
 		}
 	}
+	for _, g := range m.generated {
+		if g.name == name {
+			return true
+		}
+	}
 	return false
 }
+
+// String lists the random variables of the model with their distribution,
+// and the value of the observed ones.
+func (m *Model) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Model with %d stochastic and %d observed variables", len(m.stochastic), len(m.observed))
+	for _, v := range m.stochastic {
+		fmt.Fprintf(&b, "\n  %v", v)
+	}
+	for _, o := range m.observed {
+		fmt.Fprintf(&b, "\n  %v, observed %g", o, o.Value())
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rlouf/gmc/monitor"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/samplemv"
+)
+
+// TestSampleNoStochastic checks that sampling a model whose variables are
+// all observed panics with a clear message rather than dividing by zero.
+func TestSampleNoStochastic(t *testing.T) {
+	m := NewModel()
+	m.Observe(m.Normal("y", m.Constant(0), m.Constant(1)), 1)
+	proposal, _ := samplemv.NewProposalNormal(mat.NewSymDense(1, []float64{1}), m.Src)
+	sampler := samplemv.MetropolisHastingser{Proposal: proposal, Src: m.Src}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "no stochastic variable") {
+			t.Errorf("expected a panic about the missing variables, got %v", r)
+		}
+	}()
+	m.SampleWithOptions(10, nil, sampler, SampleOptions{MaxTraceBytes: 1024})
+}
+
+// TestSampleStopOnInterrupt checks that an interrupted run stops soon after
+// the signal rather than at the end of a long chunk.
+func TestSampleStopOnInterrupt(t *testing.T) {
+	m := NewModel()
+	m.Normal("mu", m.Constant(0), m.Constant(1))
+	proposal, _ := samplemv.NewProposalNormal(mat.NewSymDense(1, []float64{1}), m.Src)
+	sampler := samplemv.MetropolisHastingser{Proposal: proposal, Src: m.Src}
+
+	// The process interrupts itself once the first draw is made.
+	progress := make(chan monitor.Draw, 1)
+	go func() {
+		<-progress
+		process, err := os.FindProcess(os.Getpid())
+		if err == nil {
+			err = process.Signal(os.Interrupt)
+		}
+		if err != nil {
+			t.Errorf("could not send the interrupt signal: %v", err)
+		}
+	}()
+
+	result := m.SampleWithOptions(1000000, []float64{0}, sampler, SampleOptions{StopOnInterrupt: true, Progress: progress})
+	if !result.Interrupted {
+		t.Fatal("the run was not interrupted")
+	}
+	if result.Draws >= 1000000 {
+		t.Errorf("the run drew all %d samples", result.Draws)
+	}
+}
+
+// TestSamplePosteriorPredictiveParallel checks that a parallel simulation
+// does not depend on the number of workers, and that each sample is drawn
+// from the posterior sample it was assigned.
+func TestSamplePosteriorPredictiveParallel(t *testing.T) {
+	build := func() *Model {
+		m := NewModel()
+		mu := m.Normal("mu", m.Constant(0), m.Constant(1))
+		m.Observe(m.Normal("y", mu, m.Constant(0.001)), 0)
+		return m
+	}
+	trace := map[string][]float64{"mu": {-10, 0, 10}}
+
+	var previous []float64
+	for _, numWorkers := range []int{1, 4} {
+		opts := PredictiveOptions{Systematic: true, Build: build, NumWorkers: numWorkers, Seed: 42}
+		samples := build().SamplePosteriorPredictiveWithOptions(1000, trace, opts)["y"]
+		for i, y := range samples {
+			// The systematic selection goes through the trace in order,
+			// from a random offset lower than one sample.
+			low, high := trace["mu"][i*3/1000], trace["mu"][(i*3+2)/1000]
+			if math.Abs(y-low) > 0.01 && math.Abs(y-high) > 0.01 {
+				t.Fatalf("%d workers: sample %d is %f, want about %f or %f", numWorkers, i, y, low, high)
+			}
+		}
+		if previous != nil && !floats.Equal(samples, previous) {
+			t.Errorf("the samples differ with %d workers", numWorkers)
+		}
+		previous = samples
+	}
+}
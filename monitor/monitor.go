@@ -0,0 +1,134 @@
+// Package monitor serves the samples drawn during a run to browsers, so that
+// long runs can be followed live.
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// A Draw is a sample sent by the sampler while it runs.
+type Draw struct {
+	Iteration int                `json:"iteration"`
+	Values    map[string]float64 `json:"values"`
+}
+
+// A Monitor forwards the draws it receives to every connected client as
+// server-sent events. Slow clients miss draws rather than slowing down the
+// sampler.
+type Monitor struct {
+	mu      sync.Mutex
+	clients map[chan Draw]struct{}
+}
+
+// New returns a monitor without clients. Serve its Handler and pass the
+// draws of a run to Listen.
+func New() *Monitor {
+	return &Monitor{clients: make(map[chan Draw]struct{})}
+}
+
+// Listen forwards the draws received on the channel until it is closed.
+// It is typically started in its own goroutine.
+func (m *Monitor) Listen(draws <-chan Draw) {
+	for d := range draws {
+		m.mu.Lock()
+		for client := range m.clients {
+			select {
+			case client <- d:
+			default:
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// Handler returns an HTTP handler that serves a page plotting the traces at
+// its root, and the stream of draws at /events.
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", m.serveEvents)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	})
+	return mux
+}
+
+func (m *Monitor) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	client := make(chan Draw, 256)
+	m.mu.Lock()
+	m.clients[client] = struct{}{}
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.clients, client)
+		m.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case d := <-client:
+			data, err := json.Marshal(d)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+const page = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>gmc monitor</title></head>
+<body style="font-family: sans-serif">
+<h1>gmc monitor</h1>
+<div id="plots"></div>
+<script>
+var traces = {};
+function plot(name) {
+	var t = traces[name], c = t.canvas, ctx = c.getContext("2d");
+	var min = Math.min.apply(null, t.values), max = Math.max.apply(null, t.values);
+	if (min === max) { min -= 0.5; max += 0.5; }
+	ctx.clearRect(0, 0, c.width, c.height);
+	ctx.beginPath();
+	t.values.forEach(function (v, i) {
+		var x = i / t.values.length * c.width, y = c.height - (v - min) / (max - min) * c.height;
+		if (i === 0) { ctx.moveTo(x, y); } else { ctx.lineTo(x, y); }
+	});
+	ctx.strokeStyle = "steelblue";
+	ctx.stroke();
+}
+new EventSource("events").onmessage = function (e) {
+	var draw = JSON.parse(e.data);
+	Object.keys(draw.values).forEach(function (name) {
+		if (!traces[name]) {
+			var title = document.createElement("h3"), canvas = document.createElement("canvas");
+			title.textContent = name;
+			canvas.width = 600;
+			canvas.height = 120;
+			document.getElementById("plots").append(title, canvas);
+			traces[name] = {canvas: canvas, values: []};
+		}
+		var t = traces[name];
+		t.values.push(draw.values[name]);
+		if (t.values.length > 2000) { t.values.shift(); }
+		if (draw.iteration % 10 === 0) { plot(name); }
+	});
+};
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"github.com/rlouf/gmc/trace"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// NestedOptions configures a nested sampling run.
+type NestedOptions struct {
+	// NumLive is the number of live points. More live points explore
+	// multimodal posteriors more reliably and make the evidence more
+	// precise, at a proportional cost. It defaults to 400.
+	NumLive int
+
+	// NumSteps is the number of random walk steps taken to draw each new
+	// live point. It defaults to 20.
+	NumSteps int
+
+	// Tolerance stops the run when the evidence that remains in the live
+	// points is estimated to be less than this fraction of the evidence
+	// accumulated so far. It defaults to 0.01.
+	Tolerance float64
+
+	// MaxIterations caps the number of live points replaced. There is no
+	// cap when MaxIterations is 0.
+	MaxIterations int
+}
+
+// A NestedResult contains the evidence of the model and samples of its
+// posterior distribution.
+type NestedResult struct {
+	// LogEvidence is the log of the marginal likelihood of the observed
+	// variables, and LogEvidenceErr its standard error. The difference of
+	// the log-evidences of two models of the same data is their log Bayes
+	// factor.
+	LogEvidence    float64
+	LogEvidenceErr float64
+
+	// Information is the Kullback-Leibler divergence from the prior to the
+	// posterior, in nats.
+	Information float64
+
+	// Trace contains equally weighted posterior samples, as many as the
+	// effective sample size of the weighted samples.
+	Trace trace.Trace
+
+	// Weighted contains all the points visited by the run, in order of
+	// increasing likelihood, and LogWeights their normalized posterior
+	// log-weight. Weighted estimates are more precise than those of Trace.
+	Weighted   trace.Trace
+	LogWeights []float64
+
+	// Iterations is the number of live points that were replaced.
+	Iterations int
+}
+
+// SampleNested estimates the evidence of the model and draws samples from its
+// posterior distribution with nested sampling:
+//
+// "Nested sampling for general Bayesian computation" (Skilling 2006)
+// https://doi.org/10.1214/06-BA127
+//
+// A set of live points is drawn from the prior. At each iteration the live
+// point with the lowest likelihood is removed, and replaced by a point drawn
+// from the prior restricted to higher likelihoods, so that the live points
+// climb towards the posterior modes while the prior volume they occupy
+// shrinks by a factor exp(-1/NumLive). The evidence is the sum of the
+// likelihoods of the removed points weighted by the volume of prior they
+// account for. Unlike MCMC, the live points start spread over the whole prior
+// and visit all the modes, which suits multimodal, low-dimensional problems.
+//
+// New points are drawn with a random walk that starts from a randomly chosen
+// live point and accepts proposals with the prior's Metropolis-Hastings rule
+// as long as the likelihood stays above the threshold. The scale of the walk
+// follows the spread of the live points in each dimension and adapts to keep
+// about half of the proposals accepted, as in:
+//
+// "dynesty: a dynamic nested sampling package for estimating Bayesian
+// posteriors and evidences" (Speagle 2020)
+// https://doi.org/10.1093/mnras/staa278
+//
+// Too few steps leave the new points correlated with the live point they
+// start from, which biases the evidence upwards.
+//
+// It panics if none of the live points drawn from the prior has a finite
+// likelihood. The values of the stochastic variables are restored on return.
+func (m *Model) SampleNested(opts NestedOptions) *NestedResult {
+	if opts.NumLive == 0 {
+		opts.NumLive = 400
+	}
+	if opts.NumSteps == 0 {
+		opts.NumSteps = 20
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = 0.01
+	}
+	if opts.NumLive < 2 {
+		log.Panicf("nested sampling needs at least 2 live points, got %d", opts.NumLive)
+	}
+	if len(m.observed) == 0 {
+		log.Panicf("the model has no observed variable")
+	}
+
+	saved := make([]float64, len(m.stochastic))
+	for j, v := range m.stochastic {
+		saved[j] = v.Value()
+	}
+	defer m.setValues(saved)
+
+	numLive := opts.NumLive
+	live := make([]nestedPoint, numLive)
+	finite := false
+	for i := range live {
+		m.samplePrior()
+		values := make([]float64, len(m.stochastic))
+		for j, v := range m.stochastic {
+			values[j] = v.Value()
+		}
+		live[i] = m.nestedPoint(values)
+		finite = finite || !math.IsInf(live[i].logLik, -1)
+	}
+	// The evidence would stay null and the run would never stop.
+	if !finite {
+		log.Panicf("none of the %d points drawn from the prior has a finite likelihood", numLive)
+	}
+
+	var dead []nestedPoint
+	var logDeadWeights []float64
+	logZ, information := math.Inf(-1), 0.0
+	// Each iteration shrinks the prior volume by a factor exp(-1/numLive),
+	// and the removed point accounts for the volume in between.
+	logShrink := -1 / float64(numLive)
+	logWidth := math.Log(-math.Expm1(logShrink))
+	logVolume := 0.0
+	scale := 1.0
+
+	iteration := 0
+	for ; opts.MaxIterations == 0 || iteration < opts.MaxIterations; iteration++ {
+		worst, maxLogLik := 0, math.Inf(-1)
+		for i, p := range live {
+			if p.logLik < live[worst].logLik {
+				worst = i
+			}
+			maxLogLik = math.Max(maxLogLik, p.logLik)
+		}
+		if logZ > math.Inf(-1) && maxLogLik+logVolume < logZ+math.Log(opts.Tolerance) {
+			break
+		}
+
+		logWeight := logVolume + logWidth + live[worst].logLik
+		logZ, information = updateEvidence(logZ, information, logWeight, live[worst].logLik)
+		dead = append(dead, live[worst])
+		logDeadWeights = append(logDeadWeights, logWeight)
+		logVolume += logShrink
+
+		start := m.Src.Intn(numLive - 1)
+		if start >= worst {
+			start++
+		}
+		live[worst], scale = m.nestedWalk(live, live[start], live[worst].logLik, scale, opts.NumSteps)
+	}
+
+	// The live points share the remaining volume. They are sorted so that
+	// all the points are in order of increasing likelihood.
+	sort.Slice(live, func(i, j int) bool { return live[i].logLik < live[j].logLik })
+	for _, p := range live {
+		logWeight := logVolume - math.Log(float64(numLive)) + p.logLik
+		logZ, information = updateEvidence(logZ, information, logWeight, p.logLik)
+		dead = append(dead, p)
+		logDeadWeights = append(logDeadWeights, logWeight)
+	}
+	for i := range logDeadWeights {
+		logDeadWeights[i] -= logZ
+	}
+
+	result := &NestedResult{
+		LogEvidence:    logZ,
+		LogEvidenceErr: math.Sqrt(math.Max(information, 0) / float64(numLive)),
+		Information:    information,
+		Weighted:       m.nestedTrace(dead),
+		LogWeights:     logDeadWeights,
+		Iterations:     iteration,
+	}
+	result.Trace = m.nestedTrace(m.resample(dead, logDeadWeights))
+	return result
+}
+
+// A nestedPoint is a point of the prior along with its log-likelihood and
+// the value of the generated quantities.
+type nestedPoint struct {
+	values    []float64
+	generated []float64
+	logPrior  float64
+	logLik    float64
+}
+
+// nestedPoint sets the stochastic variables to the given values and returns
+// the corresponding point. The log-probabilities are -Inf when a value is out
+// of bounds.
+func (m *Model) nestedPoint(values []float64) nestedPoint {
+	p := nestedPoint{values: values, logPrior: math.Inf(-1), logLik: math.Inf(-1)}
+	for i, v := range values {
+		if err := m.stochastic[i].SetValue(v); err != nil {
+			return p
+		}
+		// Discrete variables round their value.
+		values[i] = m.stochastic[i].Value()
+	}
+	p.logPrior, p.logLik = 0, 0
+	for _, v := range m.stochastic {
+		p.logPrior += v.LogProb()
+	}
+	for _, o := range m.observed {
+		if w := m.power(o); w != 0 {
+			p.logLik += w * o.LogProb()
+		}
+	}
+	p.generated = make([]float64, len(m.generated))
+	for i, g := range m.generated {
+		p.generated[i] = g.variable.Value()
+	}
+	return p
+}
+
+// nestedWalk draws a point from the prior restricted to log-likelihoods
+// above minLogLik with a random walk that starts from a live point. The scale
+// of the proposals, relative to the spread of the live points, is adapted
+// and returned.
+func (m *Model) nestedWalk(live []nestedPoint, start nestedPoint, minLogLik, scale float64, numSteps int) (nestedPoint, float64) {
+	dim := len(m.stochastic)
+	spread := make([]float64, dim)
+	column := make([]float64, len(live))
+	for j := range spread {
+		for i, p := range live {
+			column[i] = p.values[j]
+		}
+		spread[j] = stat.StdDev(column, nil)
+		if spread[j] == 0 {
+			spread[j] = 1e-6
+		}
+	}
+
+	current := start
+	var accepted, rejected int
+	for step := 0; step < numSteps; step++ {
+		proposed := make([]float64, dim)
+		for j := range proposed {
+			proposed[j] = current.values[j] + scale*spread[j]*m.Src.NormFloat64()
+		}
+		candidate := m.nestedPoint(proposed)
+		if candidate.logLik > minLogLik && math.Log(m.Src.Float64()) < candidate.logPrior-current.logPrior {
+			current = candidate
+			accepted++
+		} else {
+			rejected++
+		}
+	}
+	if accepted > rejected {
+		scale *= math.Exp(1 / float64(accepted))
+	} else if rejected > accepted {
+		scale /= math.Exp(1 / float64(rejected))
+	}
+	return current, scale
+}
+
+// updateEvidence adds a point of log-likelihood logLik and log-weight
+// logWeight to the evidence and to the information, following Skilling
+// (2006).
+func updateEvidence(logZ, information, logWeight, logLik float64) (float64, float64) {
+	newLogZ := floats.LogSumExp([]float64{logZ, logWeight})
+	if math.IsInf(newLogZ, -1) {
+		return newLogZ, information
+	}
+	updated := math.Exp(logWeight-newLogZ)*logLik - newLogZ
+	if !math.IsInf(logZ, -1) {
+		updated += math.Exp(logZ-newLogZ) * (information + logZ)
+	}
+	return newLogZ, updated
+}
+
+// resample draws equally weighted points with systematic resampling, as
+// many as the effective sample size of the weights.
+func (m *Model) resample(points []nestedPoint, logWeights []float64) []nestedPoint {
+	weights := make([]float64, len(logWeights))
+	var sumSquares float64
+	for i, lw := range logWeights {
+		weights[i] = math.Exp(lw)
+		sumSquares += weights[i] * weights[i]
+	}
+	total := floats.Sum(weights)
+	n := int(math.Round(total * total / sumSquares))
+	if n < 1 {
+		n = 1
+	}
+
+	resampled := make([]nestedPoint, 0, n)
+	u := m.Src.Float64() / float64(n)
+	var cumulative float64
+	for i, w := range weights {
+		cumulative += w / total
+		for len(resampled) < n && u < cumulative {
+			resampled = append(resampled, points[i])
+			u += 1 / float64(n)
+		}
+	}
+	return resampled
+}
+
+// nestedTrace returns the trace of the stochastic variables and generated
+// quantities of the points.
+func (m *Model) nestedTrace(points []nestedPoint) trace.Trace {
+	samples := trace.Trace{}
+	for j, v := range m.stochastic {
+		samples[v.Name()] = make([]float64, len(points))
+		for i, p := range points {
+			samples[v.Name()][i] = p.values[j]
+		}
+	}
+	for j, g := range m.generated {
+		samples[g.name] = make([]float64, len(points))
+		for i, p := range points {
+			samples[g.name][i] = p.generated[j]
+		}
+	}
+	return samples
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// TestSampleNestedEvidence checks the evidence of a normal model with a
+// conjugate prior against its closed form: with mu ~ N(0, 1) and
+// y_i ~ N(mu, 1), the observations are jointly normal with mean 0 and
+// covariance I + 11ᵀ.
+func TestSampleNestedEvidence(t *testing.T) {
+	ys := []float64{0.8, 1.5, 0.3, 1.1, 2.0}
+	m := NewModel()
+	mu := m.Normal("mu", m.Constant(0), m.Constant(1))
+	for i, y := range ys {
+		m.Observe(m.Normal(fmt.Sprintf("y_%d", i), mu, m.Constant(1)), y)
+	}
+	mu.SetValue(0.25)
+
+	result := m.SampleNested(NestedOptions{NumLive: 200})
+
+	n := len(ys)
+	covariance := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			covariance.SetSym(i, j, 1)
+		}
+		covariance.SetSym(i, i, 2)
+	}
+	marginal, ok := distmv.NewNormal(make([]float64, n), covariance, nil)
+	if !ok {
+		t.Fatal("the covariance of the observations is not positive definite")
+	}
+	want := marginal.LogProb(ys)
+	if tolerance := 4 * result.LogEvidenceErr; math.Abs(result.LogEvidence-want) > tolerance {
+		t.Errorf("log-evidence %f ± %f, want %f", result.LogEvidence, result.LogEvidenceErr, want)
+	}
+
+	// The posterior of mu is N(Σy / (n+1), 1 / (n+1)).
+	var sum float64
+	for _, y := range ys {
+		sum += y
+	}
+	var mean float64
+	for i, x := range result.Weighted["mu"] {
+		mean += math.Exp(result.LogWeights[i]) * x
+	}
+	if want := sum / float64(n+1); math.Abs(mean-want) > 0.05 {
+		t.Errorf("posterior mean of mu %f, want %f", mean, want)
+	}
+
+	if mu.Value() != 0.25 {
+		t.Errorf("mu was left at %f, want its value before the run 0.25", mu.Value())
+	}
+}
+
+// TestSampleNestedOrder checks that the weighted points are in order of
+// increasing likelihood, the final live points included.
+func TestSampleNestedOrder(t *testing.T) {
+	m := NewModel()
+	mu := m.Normal("mu", m.Constant(0), m.Constant(1))
+	m.Observe(m.Normal("y", mu, m.Constant(1)), 1)
+
+	result := m.SampleNested(NestedOptions{NumLive: 50, MaxIterations: 100})
+
+	previous := math.Inf(-1)
+	for i, x := range result.Weighted["mu"] {
+		// The likelihood decreases with the distance to the observation.
+		logLik := -(x - 1) * (x - 1) / 2
+		if logLik < previous-1e-12 {
+			t.Fatalf("point %d has a lower likelihood than the previous one", i)
+		}
+		previous = logLik
+	}
+}
+
+// TestSampleNestedNoFiniteLikelihood checks that the run panics instead of
+// looping forever when the likelihood is null over the prior.
+func TestSampleNestedNoFiniteLikelihood(t *testing.T) {
+	m := NewModel()
+	upper := m.Normal("upper", m.Constant(0), m.Constant(0.01))
+	upper.SetValue(3)
+	// The prior of upper is far below the observation.
+	if err := m.Observe(m.Uniform("y", m.Constant(-1), upper), 1.5); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	m.SampleNested(NestedOptions{NumLive: 10})
+}
@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// The AsymmetricLaplace distribution has a log-density equal to the opposite
+// of the pinball (or check) loss of quantile Tau. Using it as a likelihood
+// yields Bayesian quantile regression: the location Mu is then the Tau-th
+// quantile of the data.
+//
+// If we note u = (x - mu) / sigma, the density is:
+//
+// tau * (1 - tau) / sigma * exp(-u * (tau - 1{u < 0}))
+type AsymmetricLaplace struct {
+	name  string
+	value float64
+	Mu    Var
+	Sigma Var
+	Tau   float64 // Tau in (0,1) is the quantile of interest
+
+	Src *rand.Rand
+}
+
+func NewAsymmetricLaplace(name string, mu, sigma Var, tau float64, src *rand.Rand) *AsymmetricLaplace {
+	defaultValue := mu.Value()
+	newAsymmetricLaplace := AsymmetricLaplace{
+		name:  name,
+		value: defaultValue,
+		Mu:    mu,
+		Sigma: sigma,
+		Tau:   tau,
+		Src:   src,
+	}
+	return &newAsymmetricLaplace
+}
+
+func (a *AsymmetricLaplace) LogProb() float64 {
+	sigma := a.Sigma.Value()
+	u := (a.value - a.Mu.Value()) / sigma
+	loss := u * a.Tau
+	if u < 0 {
+		loss = u * (a.Tau - 1)
+	}
+	return math.Log(a.Tau*(1-a.Tau)) - math.Log(sigma) - loss
+}
+
+// Rand draws a sample by inverting the cumulative distribution function.
+func (a *AsymmetricLaplace) Rand() float64 {
+	uniform := distuv.Uniform{Min: 0, Max: 1, Src: a.Src}
+	p := uniform.Rand()
+
+	var u float64
+	if p < a.Tau {
+		u = math.Log(p/a.Tau) / (1 - a.Tau)
+	} else {
+		u = -math.Log((1-p)/(1-a.Tau)) / a.Tau
+	}
+	return a.Mu.Value() + a.Sigma.Value()*u
+}
+
+func (a *AsymmetricLaplace) Name() string {
+	return a.name
+}
+
+func (a *AsymmetricLaplace) Value() float64 {
+	return a.value
+}
+
+func (a *AsymmetricLaplace) SetValue(newValue float64) error {
+	a.value = newValue
+	return nil
+}
+
+func (a *AsymmetricLaplace) String() string {
+	return fmt.Sprintf("%s ~ AsymmetricLaplace(Mu=%s, Sigma=%s, Tau=%s)", a.name, describe(a.Mu), describe(a.Sigma), formatFloat(a.Tau))
+}
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"fmt"
 	"math"
 
 	"golang.org/x/exp/rand"
@@ -53,3 +54,16 @@ func (b *Bernoulli) SetValue(newValue float64) error {
 
 	return nil
 }
+
+func (b *Bernoulli) String() string {
+	return fmt.Sprintf("%s ~ Bernoulli(P=%s)", b.name, describe(b.P))
+}
+
+func (b *Bernoulli) Mean() float64 {
+	return b.P.Value()
+}
+
+func (b *Bernoulli) Variance() float64 {
+	p := b.P.Value()
+	return p * (1 - p)
+}
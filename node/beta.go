@@ -33,6 +33,11 @@ func (b *Beta) LogProb() float64 {
 	return dist.LogProb(b.Value())
 }
 
+func (b *Beta) CDF(x float64) float64 {
+	dist := distuv.Beta{Alpha: b.Alpha.Value(), Beta: b.Beta.Value()}
+	return dist.CDF(x)
+}
+
 func (b *Beta) Rand() float64 {
 	beta := distuv.Beta{Alpha: b.Alpha.Value(), Beta: b.Beta.Value(), Src: b.Src}
 	return beta.Rand()
@@ -55,3 +60,17 @@ func (b *Beta) SetValue(newValue float64) error {
 
 	return nil
 }
+
+func (b *Beta) String() string {
+	return fmt.Sprintf("%s ~ Beta(Alpha=%s, Beta=%s)", b.name, describe(b.Alpha), describe(b.Beta))
+}
+
+func (b *Beta) Mean() float64 {
+	alpha, beta := b.Alpha.Value(), b.Beta.Value()
+	return alpha / (alpha + beta)
+}
+
+func (b *Beta) Variance() float64 {
+	alpha, beta := b.Alpha.Value(), b.Beta.Value()
+	return alpha * beta / ((alpha + beta) * (alpha + beta) * (alpha + beta + 1))
+}
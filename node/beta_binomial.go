@@ -0,0 +1,105 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// BetaBinomial is a random variable that counts the successes among N
+// Bernoulli trials whose probability of success follows a Beta distribution
+// of parameters Alpha and Beta. The probability is integrated out of the
+// log-probability, so over-dispersed binomial outcomes can be modeled
+// without one latent Beta variable per outcome:
+//
+// P(k) = C(N, k) * B(k + Alpha, N - k + Beta) / B(Alpha, Beta)
+type BetaBinomial struct {
+	name  string
+	value float64
+	N     float64 // N is the total number of Bernoulli trials > 0
+	Alpha Var
+	Beta  Var
+
+	Src *rand.Rand
+}
+
+func NewBetaBinomial(name string, N float64, alpha, beta Var, src *rand.Rand) *BetaBinomial {
+	defaultValue := math.Round(N * alpha.Value() / (alpha.Value() + beta.Value()))
+	newBetaBinomial := BetaBinomial{
+		name:  name,
+		value: defaultValue,
+		N:     N,
+		Alpha: alpha,
+		Beta:  beta,
+		Src:   src,
+	}
+	return &newBetaBinomial
+}
+
+func (b *BetaBinomial) LogProb() float64 {
+	alpha, beta := b.Alpha.Value(), b.Beta.Value()
+	if alpha <= 0 || beta <= 0 {
+		return math.Inf(-1)
+	}
+	k, n := b.value, b.N
+	return logChoose(n, k) + logBeta(k+alpha, n-k+beta) - logBeta(alpha, beta)
+}
+
+// Rand draws the probability of success from the Beta distribution, then the
+// number of successes.
+func (b *BetaBinomial) Rand() float64 {
+	p := distuv.Beta{Alpha: b.Alpha.Value(), Beta: b.Beta.Value(), Src: b.Src}
+	dist := distuv.Binomial{N: b.N, P: p.Rand(), Src: b.Src}
+	return dist.Rand()
+}
+
+func (b *BetaBinomial) Name() string {
+	return b.name
+}
+
+func (b *BetaBinomial) Value() float64 {
+	return b.value
+}
+
+func (b *BetaBinomial) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 || roundedVal > b.N {
+		return &OutOfBoundsErr{fmt.Sprintf("A beta-binomial random variable can only take the integers between 0 and %s as values, got %f", formatFloat(b.N), newValue)}
+	}
+	b.value = roundedVal
+
+	return nil
+}
+
+func (b *BetaBinomial) String() string {
+	return fmt.Sprintf("%s ~ BetaBinomial(N=%s, Alpha=%s, Beta=%s)", b.name, formatFloat(b.N), describe(b.Alpha), describe(b.Beta))
+}
+
+func (b *BetaBinomial) Mean() float64 {
+	alpha, beta := b.Alpha.Value(), b.Beta.Value()
+	return b.N * alpha / (alpha + beta)
+}
+
+func (b *BetaBinomial) Variance() float64 {
+	alpha, beta := b.Alpha.Value(), b.Beta.Value()
+	s := alpha + beta
+	return b.N * alpha * beta * (s + b.N) / (s * s * (s + 1))
+}
+
+// logBeta returns the logarithm of the Beta function.
+func logBeta(a, b float64) float64 {
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	return la + lb - lab
+}
+
+// logChoose returns the logarithm of the binomial coefficient C(n, k).
+func logChoose(n, k float64) float64 {
+	ln, _ := math.Lgamma(n + 1)
+	lk, _ := math.Lgamma(k + 1)
+	lnk, _ := math.Lgamma(n - k + 1)
+	return ln - lk - lnk
+}
@@ -0,0 +1,24 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBetaBinomialLogProb(t *testing.T) {
+	b := NewBetaBinomial("k", 5, NewConstant(2), NewConstant(3), nil)
+	b.SetValue(2)
+	// C(5, 2) B(4, 6) / B(2, 3) = 10 (1 / 504) / (1 / 12)
+	if got, want := b.LogProb(), math.Log(120.0/504); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+
+	var mass float64
+	for k := 0; k <= 5; k++ {
+		b.SetValue(float64(k))
+		mass += math.Exp(b.LogProb())
+	}
+	if math.Abs(mass-1) > 1e-12 {
+		t.Errorf("the probabilities sum to %f", mass)
+	}
+}
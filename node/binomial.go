@@ -1,6 +1,7 @@
 package node
 
 import (
+	"fmt"
 	"math"
 
 	"golang.org/x/exp/rand"
@@ -33,6 +34,11 @@ func (b *Binomial) LogProb() float64 {
 	return dist.LogProb(b.value)
 }
 
+func (b *Binomial) CDF(x float64) float64 {
+	dist := distuv.Binomial{N: b.N, P: b.P.Value()}
+	return dist.CDF(x)
+}
+
 func (b *Binomial) Rand() float64 {
 	dist := distuv.Binomial{N: b.N, P: b.P.Value(), Src: b.Src}
 	return dist.Rand()
@@ -55,3 +61,16 @@ func (b *Binomial) SetValue(newValue float64) error {
 
 	return nil
 }
+
+func (b *Binomial) String() string {
+	return fmt.Sprintf("%s ~ Binomial(N=%s, P=%s)", b.name, formatFloat(b.N), describe(b.P))
+}
+
+func (b *Binomial) Mean() float64 {
+	return b.N * b.P.Value()
+}
+
+func (b *Binomial) Variance() float64 {
+	p := b.P.Value()
+	return b.N * p * (1 - p)
+}
@@ -0,0 +1,74 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Categorical is a random variable that takes the value k in {0, ..., K-1}
+// with probability P[k]. The probabilities are normalized, so they only need
+// to be proportional to the probability of each category.
+type Categorical struct {
+	name  string
+	value float64
+	P     []Var
+
+	Src *rand.Rand
+}
+
+func NewCategorical(name string, p []Var, src *rand.Rand) *Categorical {
+	defaultValue := 0.0
+	newCategorical := Categorical{
+		name:  name,
+		value: defaultValue,
+		P:     p,
+		Src:   src,
+	}
+	return &newCategorical
+}
+
+func (c *Categorical) dist(src *rand.Rand) distuv.Categorical {
+	weights := make([]float64, len(c.P))
+	for k, p := range c.P {
+		weights[k] = p.Value()
+	}
+	return distuv.NewCategorical(weights, src)
+}
+
+func (c *Categorical) LogProb() float64 {
+	return c.dist(nil).LogProb(c.value)
+}
+
+func (c *Categorical) Rand() float64 {
+	return c.dist(c.Src).Rand()
+}
+
+func (c *Categorical) Name() string {
+	return c.name
+}
+
+func (c *Categorical) Value() float64 {
+	return c.value
+}
+
+func (c *Categorical) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 || roundedVal >= float64(len(c.P)) {
+		return &OutOfBoundsErr{fmt.Sprintf("A categorical random variable with %d categories can only take the integers between 0 and %d as values, got %f", len(c.P), len(c.P)-1, newValue)}
+	}
+	c.value = roundedVal
+
+	return nil
+}
+
+func (c *Categorical) String() string {
+	probs := make([]string, len(c.P))
+	for k, p := range c.P {
+		probs[k] = describe(p)
+	}
+	return fmt.Sprintf("%s ~ Categorical(P=[%s])", c.name, strings.Join(probs, ", "))
+}
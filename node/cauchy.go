@@ -0,0 +1,63 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// Cauchy is a random variable that follows a Cauchy distribution of location
+// Mu and scale Scale. Its tails are so heavy that it has no mean, which makes
+// it a weakly informative prior that lets the data override it.
+type Cauchy struct {
+	name  string
+	value float64
+	Mu    Var
+	Scale Var
+
+	Src *rand.Rand
+}
+
+func NewCauchy(name string, mu, scale Var, src *rand.Rand) *Cauchy {
+	defaultValue := mu.Value()
+	newCauchy := Cauchy{
+		name:  name,
+		value: defaultValue,
+		Mu:    mu,
+		Scale: scale,
+		Src:   src,
+	}
+	return &newCauchy
+}
+
+func (c *Cauchy) LogProb() float64 {
+	scale := c.Scale.Value()
+	z := (c.value - c.Mu.Value()) / scale
+	return -math.Log(math.Pi*scale) - math.Log1p(z*z)
+}
+
+func (c *Cauchy) CDF(x float64) float64 {
+	return 0.5 + math.Atan((x-c.Mu.Value())/c.Scale.Value())/math.Pi
+}
+
+func (c *Cauchy) Rand() float64 {
+	return c.Mu.Value() + c.Scale.Value()*math.Tan(math.Pi*(randFloat64(c.Src)-0.5))
+}
+
+func (c *Cauchy) Name() string {
+	return c.name
+}
+
+func (c *Cauchy) Value() float64 {
+	return c.value
+}
+
+func (c *Cauchy) SetValue(newValue float64) error {
+	c.value = newValue
+	return nil
+}
+
+func (c *Cauchy) String() string {
+	return fmt.Sprintf("%s ~ Cauchy(Mu=%s, Scale=%s)", c.name, describe(c.Mu), describe(c.Scale))
+}
@@ -0,0 +1,18 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCauchyLogProb(t *testing.T) {
+	c := NewCauchy("x", NewConstant(1), NewConstant(2), nil)
+	c.SetValue(3)
+	// 1 / (pi scale (1 + ((x - mu) / scale)^2))
+	if got, want := c.LogProb(), -math.Log(math.Pi*2*2); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+	if got, want := c.CDF(3), 0.75; math.Abs(got-want) > 1e-12 {
+		t.Errorf("got a CDF of %f, want %f", got, want)
+	}
+}
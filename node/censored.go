@@ -0,0 +1,154 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+)
+
+// Censored is an observation of another random variable, Dist, by an
+// instrument that cannot measure values outside of [Lower, Upper], such as
+// the survival time of a patient still alive at the end of a study. A value
+// lower than or equal to Lower is left-censored: the variable is only known
+// to be lower than Lower, and its log-probability is log CDF(Lower).
+// Likewise a value greater than or equal to Upper is right-censored, with
+// log-probability log P(X >= Upper): log(1 - CDF(Upper)) for continuous
+// distributions, and log(1 - CDF(Upper - 1)) for counts, since the mass at
+// Upper is part of the censored region. Values in between are exact
+// measurements whose log-probability is that of Dist. Either bound can be
+// infinite.
+//
+// An interval-censored observation is only known to be in (Lower, Upper],
+// e.g. an event that happened between two visits, whatever its value: its
+// log-probability is log(CDF(Upper) - CDF(Lower)).
+//
+// Dist must implement CDF. It is not part of the model: its parameters can be
+// variables of the model, and the censored variable holds the observation.
+// Censored variables are meant to be observed, one per data point, each with
+// its own bounds.
+type Censored struct {
+	value    float64
+	Dist     RandVar
+	Lower    float64
+	Upper    float64
+	Interval bool // whether the observation is interval-censored
+}
+
+// NewCensored returns an observation of dist that is censored outside of
+// [lower, upper]. The censored variable takes the name of dist.
+func NewCensored(dist RandVar, lower, upper float64) *Censored {
+	return newCensored(dist, lower, upper, false)
+}
+
+// NewIntervalCensored returns an observation of dist that is only known to
+// be in (lower, upper]. The censored variable takes the name of dist.
+func NewIntervalCensored(dist RandVar, lower, upper float64) *Censored {
+	return newCensored(dist, lower, upper, true)
+}
+
+func newCensored(dist RandVar, lower, upper float64, interval bool) *Censored {
+	if _, ok := dist.(CDF); !ok {
+		log.Panicf("cannot censor %s: its distribution has no CDF", dist.Name())
+	}
+	if lower >= upper {
+		log.Panicf("the lower bound of the censoring must be lower than the upper bound, got [%f, %f]", lower, upper)
+	}
+	c := &Censored{value: dist.Value(), Dist: dist, Lower: lower, Upper: upper, Interval: interval}
+	if interval && (c.value <= lower || c.value > upper) {
+		c.value = upper
+	}
+	return c
+}
+
+// cdf returns the CDF of Dist, which is exactly 0 and 1 at -Inf and +Inf.
+func (c *Censored) cdf(x float64) float64 {
+	switch {
+	case math.IsInf(x, -1):
+		return 0
+	case math.IsInf(x, 1):
+		return 1
+	}
+	return c.Dist.(CDF).CDF(x)
+}
+
+// cdfBelow returns the left limit of the CDF of Dist at x, the probability
+// that Dist is strictly lower than x. It is the CDF at x for continuous
+// distributions, and excludes the mass at x of discrete ones.
+func (c *Censored) cdfBelow(x float64) float64 {
+	if math.IsInf(x, 0) {
+		return c.cdf(x)
+	}
+	return c.cdf(math.Nextafter(x, math.Inf(-1)))
+}
+
+// IsCensored returns whether the current value is censored rather than an
+// exact measurement.
+func (c *Censored) IsCensored() bool {
+	return c.Interval || c.value <= c.Lower || c.value >= c.Upper
+}
+
+func (c *Censored) LogProb() float64 {
+	switch {
+	case c.Interval:
+		if c.value <= c.Lower || c.value > c.Upper {
+			return math.Inf(-1)
+		}
+		return math.Log(c.cdf(c.Upper) - c.cdf(c.Lower))
+	case c.value <= c.Lower:
+		return math.Log(c.cdf(c.Lower))
+	case c.value >= c.Upper:
+		return math.Log1p(-c.cdfBelow(c.Upper))
+	}
+	return c.Dist.LogProb()
+}
+
+// Rand returns a random observation: a draw of Dist clamped to [Lower,
+// Upper], or for an interval-censored observation a draw of Dist given that
+// it falls in (Lower, Upper].
+func (c *Censored) Rand() float64 {
+	if c.Interval {
+		t := Truncated{Dist: c.Dist, Lower: c.Lower, Upper: c.Upper}
+		return t.Rand()
+	}
+	return math.Max(c.Lower, math.Min(c.Upper, c.Dist.Rand()))
+}
+
+func (c *Censored) Name() string {
+	return c.Dist.Name()
+}
+
+func (c *Censored) Value() float64 {
+	return c.value
+}
+
+// SetValue sets the observation. Exact measurements must be in the support
+// of Dist, and interval-censored observations in (Lower, Upper].
+func (c *Censored) SetValue(newValue float64) error {
+	switch {
+	case c.Interval:
+		if newValue <= c.Lower || newValue > c.Upper {
+			return &OutOfBoundsErr{fmt.Sprintf("%s is censored to (%s, %s], got %f", c.Name(), formatFloat(c.Lower), formatFloat(c.Upper), newValue)}
+		}
+	case newValue > c.Lower && newValue < c.Upper:
+		if err := c.Dist.SetValue(newValue); err != nil {
+			return err
+		}
+		newValue = c.Dist.Value()
+	}
+	c.value = newValue
+	return nil
+}
+
+func (c *Censored) String() string {
+	description := fmt.Sprint(c.Dist)
+	// Dist is described by its distribution only.
+	if i := strings.Index(description, " ~ "); i >= 0 {
+		description = description[i+3:]
+	}
+	kind := "Censored"
+	if c.Interval {
+		kind = "IntervalCensored"
+	}
+	return fmt.Sprintf("%s ~ %s(%s, Lower=%s, Upper=%s)", c.Name(), kind, description, formatFloat(c.Lower), formatFloat(c.Upper))
+}
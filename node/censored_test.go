@@ -0,0 +1,44 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestCensoredLogProb(t *testing.T) {
+	std := distuv.UnitNormal
+	cases := []struct{ x, want float64 }{
+		{-3, math.Log(std.CDF(-1))},    // left-censored
+		{-1, math.Log(std.CDF(-1))},    // at the lower bound
+		{0.5, std.LogProb(0.5)},        // exact measurement
+		{2.5, math.Log1p(-std.CDF(2))}, // right-censored
+	}
+	for _, c := range cases {
+		censored := NewCensored(NewNormal("x", NewConstant(0), NewConstant(1), nil), -1, 2)
+		if err := censored.SetValue(c.x); err != nil {
+			t.Fatal(err)
+		}
+		if got := censored.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("x=%g: got %f, want %f", c.x, got, c.want)
+		}
+	}
+
+	// A right-censored count includes the upper bound: P(X >= 5).
+	poisson := NewCensored(NewPoisson("k", NewConstant(3), nil), -1, 5)
+	poisson.SetValue(7)
+	want := math.Log1p(-distuv.Poisson{Lambda: 3}.CDF(4))
+	if got := poisson.LogProb(); !closeTo(got, want) {
+		t.Errorf("right-censored Poisson: got %f, want %f", got, want)
+	}
+
+	interval := NewIntervalCensored(NewNormal("x", NewConstant(0), NewConstant(1), nil), 0, 1)
+	interval.SetValue(0.5)
+	if got, want := interval.LogProb(), math.Log(std.CDF(1)-std.CDF(0)); !closeTo(got, want) {
+		t.Errorf("interval-censored: got %f, want %f", got, want)
+	}
+	if err := interval.SetValue(2); err == nil {
+		t.Error("interval-censored: accepted a value outside of the interval")
+	}
+}
@@ -0,0 +1,77 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ChiSquared is a random variable that follows a chi-squared distribution
+// with K degrees of freedom, the distribution of the sum of the squares of K
+// independent standard normal variables.
+type ChiSquared struct {
+	name  string
+	value float64
+	K     Var
+
+	Src *rand.Rand
+}
+
+func NewChiSquared(name string, k Var, src *rand.Rand) *ChiSquared {
+	defaultValue := k.Value()
+	newChiSquared := ChiSquared{
+		name:  name,
+		value: defaultValue,
+		K:     k,
+		Src:   src,
+	}
+	return &newChiSquared
+}
+
+func (c *ChiSquared) LogProb() float64 {
+	dist := distuv.ChiSquared{K: c.K.Value()}
+	return dist.LogProb(c.value)
+}
+
+func (c *ChiSquared) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	dist := distuv.ChiSquared{K: c.K.Value()}
+	return dist.CDF(x)
+}
+
+func (c *ChiSquared) Rand() float64 {
+	dist := distuv.ChiSquared{K: c.K.Value(), Src: c.Src}
+	return dist.Rand()
+}
+
+func (c *ChiSquared) Name() string {
+	return c.name
+}
+
+func (c *ChiSquared) Value() float64 {
+	return c.value
+}
+
+func (c *ChiSquared) SetValue(newValue float64) error {
+	if newValue < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("ChiSquared is defined on [0,+inf), got value %f", newValue)}
+	}
+	c.value = newValue
+
+	return nil
+}
+
+func (c *ChiSquared) String() string {
+	return fmt.Sprintf("%s ~ ChiSquared(K=%s)", c.name, describe(c.K))
+}
+
+func (c *ChiSquared) Mean() float64 {
+	return c.K.Value()
+}
+
+func (c *ChiSquared) Variance() float64 {
+	return 2 * c.K.Value()
+}
@@ -1,8 +1,10 @@
 package node
 
 import (
+	"fmt"
 	"log"
 	"math"
+	"strings"
 )
 
 type Constant struct {
@@ -17,6 +19,10 @@ func (c *Constant) Value() float64 {
 	return c.value
 }
 
+func (c *Constant) String() string {
+	return formatFloat(c.value)
+}
+
 // The SumGate represents the sum of two variables.
 // Its value is equal to the sum of the values of the variables.
 type SumGate struct {
@@ -28,6 +34,10 @@ func (s SumGate) Value() float64 {
 	return s.X.Value() + s.Y.Value()
 }
 
+func (s SumGate) String() string {
+	return fmt.Sprintf("(%s + %s)", describe(s.X), describe(s.Y))
+}
+
 // The ProdGate represents the product of two variables.
 // Its value is equal to the product of the values of the variables.
 type ProdGate struct {
@@ -39,6 +49,10 @@ func (p ProdGate) Value() float64 {
 	return p.X.Value() * p.Y.Value()
 }
 
+func (p ProdGate) String() string {
+	return fmt.Sprintf("(%s * %s)", describe(p.X), describe(p.Y))
+}
+
 // The Logistic gate applies the logistic function to a variable.
 //
 // If we note x the value of the variable X, the value of the logistic gate is
@@ -61,6 +75,10 @@ func (l *LogisticGate) Value() float64 {
 	return z / (1 + z)
 }
 
+func (l *LogisticGate) String() string {
+	return fmt.Sprintf("logistic(%s)", describe(l.X))
+}
+
 // The LogitGate applies the logit (or log-odds) function to a variable.
 // If we note x the value of the variable X, the value of the LogitGate is
 //
@@ -83,6 +101,10 @@ func (l *LogitGate) Value() float64 {
 	return math.Log(1 / (1 - v))
 }
 
+func (l *LogitGate) String() string {
+	return fmt.Sprintf("logit(%s)", describe(l.X))
+}
+
 // The SwitchGate chooses between the values of two variables depending on the
 // value of a third variable and a threshold.
 //
@@ -104,3 +126,131 @@ func (s *SwitchGate) Value() float64 {
 	}
 	return s.Right.Value()
 }
+
+func (s *SwitchGate) String() string {
+	return fmt.Sprintf("switch(%s <= %s ? %s : %s)", describe(s.Switch), formatFloat(s.Threshold), describe(s.Left), describe(s.Right))
+}
+
+// The ExpGate applies the exponential function to a variable. It maps a
+// variable defined on the real line to a positive value, e.g. a normal prior
+// on the logarithm of a positive coefficient.
+type ExpGate struct {
+	X Var
+}
+
+func (e *ExpGate) Value() float64 {
+	return math.Exp(e.X.Value())
+}
+
+func (e *ExpGate) String() string {
+	return fmt.Sprintf("exp(%s)", describe(e.X))
+}
+
+// The AdstockGate represents the carry-over effect of a series of inputs,
+// e.g. advertising spend, whose effect decays geometrically over time.
+//
+// If we note x the series and d the value of the Decay variable, the value
+// of the gate at time t is the normalized convolution:
+//
+// sum_{l=0}^{MaxLag} d^l * x[t-l] / sum_{l=0}^{MaxLag} d^l
+//
+// The terms before the beginning of the series are ignored.
+type AdstockGate struct {
+	Series []float64
+	T      int
+	MaxLag int
+	Decay  Var
+}
+
+func (a *AdstockGate) Value() float64 {
+	d := a.Decay.Value()
+	if d < 0 || d > 1 {
+		log.Panicf("the adstock decay must be in [0,1], got %f", d)
+	}
+
+	var total, norm float64
+	weight := 1.0
+	for l := 0; l <= a.MaxLag; l++ {
+		if a.T-l >= 0 {
+			total += weight * a.Series[a.T-l]
+		}
+		norm += weight
+		weight *= d
+	}
+	return total / norm
+}
+
+func (a *AdstockGate) String() string {
+	return fmt.Sprintf("adstock(t=%d, MaxLag=%d, Decay=%s)", a.T, a.MaxLag, describe(a.Decay))
+}
+
+// The HillGate represents the saturation of the response to an input: the
+// response increases with the input until it levels off at 1.
+//
+// If we note x the value of the variable X, k the value of HalfSaturation
+// and s the value of Slope, the value of the gate is:
+//
+// x^s / (x^s + k^s)
+//
+// For more info see: https://en.wikipedia.org/wiki/Hill_equation_(biochemistry)
+type HillGate struct {
+	X              Var
+	HalfSaturation Var
+	Slope          Var
+}
+
+func (h *HillGate) Value() float64 {
+	x := h.X.Value()
+	if x < 0 {
+		log.Panicf("the Hill function is defined for positive values, got %f", x)
+	}
+	if x == 0 {
+		return 0
+	}
+	// 1 / (1 + (k/x)^s) avoids the overflow of x^s for large inputs.
+	return 1 / (1 + math.Pow(h.HalfSaturation.Value()/x, h.Slope.Value()))
+}
+
+func (h *HillGate) String() string {
+	return fmt.Sprintf("hill(%s, HalfSaturation=%s, Slope=%s)", describe(h.X), describe(h.HalfSaturation), describe(h.Slope))
+}
+
+// The NegativeSumGate represents the opposite of the sum of variables. It
+// completes a set of free variables so that they sum to zero.
+type NegativeSumGate struct {
+	X []Var
+}
+
+func (n *NegativeSumGate) Value() float64 {
+	var sum float64
+	for _, x := range n.X {
+		sum += x.Value()
+	}
+	return -sum
+}
+
+func (n *NegativeSumGate) String() string {
+	terms := make([]string, len(n.X))
+	for i, x := range n.X {
+		terms[i] = describe(x)
+	}
+	return fmt.Sprintf("-(%s)", strings.Join(terms, " + "))
+}
+
+// The SqrtGate applies the square root function to a variable. The value of
+// the variable must be positive.
+type SqrtGate struct {
+	X Var
+}
+
+func (s *SqrtGate) Value() float64 {
+	v := s.X.Value()
+	if v < 0 {
+		log.Panicf("the square root is defined on [0,+inf), got %f", v)
+	}
+	return math.Sqrt(v)
+}
+
+func (s *SqrtGate) String() string {
+	return fmt.Sprintf("sqrt(%s)", describe(s.X))
+}
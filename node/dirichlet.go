@@ -0,0 +1,116 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/rand"
+)
+
+// The Dirichlet distribution is a distribution over probability vectors, i.e.
+// vectors of positive values that sum to 1.
+//
+// Samplers explore unconstrained scalar variables, so the vector is
+// represented by K independent Gamma(Alpha_k, 1) variables g_k, and its
+// components are their normalized values:
+//
+// p_k = g_k / sum_j g_j
+//
+// which follow a Dirichlet(Alpha) distribution. The Gamma variables are the
+// stochastic variables of the model; the components are deterministic
+// functions of them.
+//
+// For more info see: https://en.wikipedia.org/wiki/Dirichlet_distribution
+type Dirichlet struct {
+	name   string
+	Gammas []*Gamma
+}
+
+// NewDirichlet returns a Dirichlet vector with one component per
+// concentration parameter. The Gamma variables are named "<name>_gamma_<k>".
+func NewDirichlet(name string, alphas []Var, src *rand.Rand) *Dirichlet {
+	one := NewConstant(1)
+	gammas := make([]*Gamma, len(alphas))
+	for k, alpha := range alphas {
+		gammas[k] = NewGamma(fmt.Sprintf("%s_gamma_%d", name, k), alpha, one, src)
+	}
+	return &Dirichlet{name: name, Gammas: gammas}
+}
+
+func (d *Dirichlet) Name() string {
+	return d.name
+}
+
+// Len returns the number of components of the vector.
+func (d *Dirichlet) Len() int {
+	return len(d.Gammas)
+}
+
+// Values returns the current value of the vector.
+func (d *Dirichlet) Values() []float64 {
+	values := make([]float64, len(d.Gammas))
+	var sum float64
+	for k, g := range d.Gammas {
+		values[k] = g.Value()
+		sum += values[k]
+	}
+	for k := range values {
+		values[k] /= sum
+	}
+	return values
+}
+
+// Rand returns a random vector drawn from the distribution.
+func (d *Dirichlet) Rand() []float64 {
+	values := make([]float64, len(d.Gammas))
+	var sum float64
+	for k, g := range d.Gammas {
+		values[k] = g.Rand()
+		sum += values[k]
+	}
+	for k := range values {
+		values[k] /= sum
+	}
+	return values
+}
+
+// Component returns the k-th component of the vector as a variable that can
+// be used as the parameter of other nodes.
+func (d *Dirichlet) Component(k int) Var {
+	return &DirichletComponent{Dirichlet: d, K: k}
+}
+
+// Components returns all the components of the vector.
+func (d *Dirichlet) Components() []Var {
+	components := make([]Var, len(d.Gammas))
+	for k := range components {
+		components[k] = d.Component(k)
+	}
+	return components
+}
+
+func (d *Dirichlet) String() string {
+	alphas := make([]string, len(d.Gammas))
+	for k, g := range d.Gammas {
+		alphas[k] = describe(g.Alpha)
+	}
+	return fmt.Sprintf("%s ~ Dirichlet(Alpha=[%s])", d.name, strings.Join(alphas, ", "))
+}
+
+// A DirichletComponent is one of the components of a Dirichlet vector.
+type DirichletComponent struct {
+	Dirichlet *Dirichlet
+	K         int
+}
+
+func (c *DirichletComponent) Value() float64 {
+	var sum float64
+	for _, g := range c.Dirichlet.Gammas {
+		sum += g.Value()
+	}
+	return c.Dirichlet.Gammas[c.K].Value() / sum
+}
+
+func (c *DirichletComponent) String() string {
+	return fmt.Sprintf("%s[%d]", c.Dirichlet.name, c.K)
+}
@@ -0,0 +1,173 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// The DirichletMultinomial distribution counts the outcomes of N trials that
+// fall in each of K categories, the probabilities of the categories
+// following a Dirichlet distribution of concentrations Alpha. The
+// probabilities are integrated out, so over-dispersed categorical counts can
+// be modeled without a latent Dirichlet vector.
+//
+// Like the Multinomial, the count vector is represented by one variable per
+// category, "<name>_<k>", and its probability is factored in a sequence of
+// beta-binomial distributions: the count of category k is the number of
+// successes among the trials that did not fall in the previous categories,
+// with the concentrations Alpha[k] and Alpha[k+1] + ... + Alpha[K-1], and the
+// last count is whatever trials remain. The counts are meant to be observed.
+//
+// For more info see: https://en.wikipedia.org/wiki/Dirichlet-multinomial_distribution
+type DirichletMultinomial struct {
+	name       string
+	N          float64 // N is the total number of trials > 0
+	Alpha      []Var
+	Components []*DirichletMultinomialComponent
+
+	draw []float64 // last vector drawn by Rand
+	Src  *rand.Rand
+}
+
+// NewDirichletMultinomial returns a Dirichlet-multinomial vector with one
+// count per category. All the trials initially fall in the last category.
+func NewDirichletMultinomial(name string, N float64, alpha []Var, src *rand.Rand) *DirichletMultinomial {
+	d := &DirichletMultinomial{name: name, N: N, Alpha: alpha, Src: src}
+	for k := range alpha {
+		d.Components = append(d.Components, &DirichletMultinomialComponent{
+			name:                 fmt.Sprintf("%s_%d", name, k),
+			DirichletMultinomial: d,
+			K:                    k,
+		})
+	}
+	d.Components[len(alpha)-1].value = N
+	return d
+}
+
+func (d *DirichletMultinomial) Name() string {
+	return d.name
+}
+
+// Values returns the current counts.
+func (d *DirichletMultinomial) Values() []float64 {
+	values := make([]float64, len(d.Components))
+	for k, c := range d.Components {
+		values[k] = c.value
+	}
+	return values
+}
+
+// Rand returns a random count vector drawn from the distribution.
+func (d *DirichletMultinomial) Rand() []float64 {
+	values := make([]float64, len(d.Alpha))
+	remaining := d.N
+	for k := range values {
+		if k == len(values)-1 {
+			values[k] = remaining
+			break
+		}
+		if remaining > 0 {
+			p := distuv.Beta{Alpha: d.Alpha[k].Value(), Beta: d.rest(k), Src: d.Src}
+			dist := distuv.Binomial{N: remaining, P: p.Rand(), Src: d.Src}
+			values[k] = dist.Rand()
+		}
+		remaining -= values[k]
+	}
+	return values
+}
+
+// rest returns the sum of the concentrations of the categories after k.
+func (d *DirichletMultinomial) rest(k int) float64 {
+	var rest float64
+	for _, alpha := range d.Alpha[k+1:] {
+		rest += alpha.Value()
+	}
+	return rest
+}
+
+func (d *DirichletMultinomial) String() string {
+	return fmt.Sprintf("%s ~ %s", d.name, d.distribution())
+}
+
+func (d *DirichletMultinomial) distribution() string {
+	alphas := make([]string, len(d.Alpha))
+	for k, alpha := range d.Alpha {
+		alphas[k] = describe(alpha)
+	}
+	return fmt.Sprintf("DirichletMultinomial(N=%s, Alpha=[%s])", formatFloat(d.N), strings.Join(alphas, ", "))
+}
+
+// A DirichletMultinomialComponent is the count of one of the categories of
+// a Dirichlet-multinomial vector.
+type DirichletMultinomialComponent struct {
+	name                 string
+	value                float64
+	DirichletMultinomial *DirichletMultinomial
+	K                    int
+}
+
+// remaining returns the number of trials that do not fall in the categories
+// before this one.
+func (c *DirichletMultinomialComponent) remaining() float64 {
+	remaining := c.DirichletMultinomial.N
+	for _, previous := range c.DirichletMultinomial.Components[:c.K] {
+		remaining -= previous.value
+	}
+	return remaining
+}
+
+// LogProb returns the log-probability of the count given the counts of the
+// previous categories. The sum over the components is the log-probability of
+// the vector.
+func (c *DirichletMultinomialComponent) LogProb() float64 {
+	d := c.DirichletMultinomial
+	remaining := c.remaining()
+	if c.K == len(d.Components)-1 {
+		if c.value == remaining {
+			return 0
+		}
+		return math.Inf(-1)
+	}
+	alpha, beta := d.Alpha[c.K].Value(), d.rest(c.K)
+	if c.value > remaining || alpha <= 0 || beta <= 0 {
+		return math.Inf(-1)
+	}
+	k := c.value
+	return logChoose(remaining, k) + logBeta(k+alpha, remaining-k+beta) - logBeta(alpha, beta)
+}
+
+// Rand returns the count of the category in a random vector. The first
+// component draws a new vector and the others return their count in it, so
+// that drawing the components in order yields counts that sum to N.
+func (c *DirichletMultinomialComponent) Rand() float64 {
+	if c.K == 0 || c.DirichletMultinomial.draw == nil {
+		c.DirichletMultinomial.draw = c.DirichletMultinomial.Rand()
+	}
+	return c.DirichletMultinomial.draw[c.K]
+}
+
+func (c *DirichletMultinomialComponent) Name() string {
+	return c.name
+}
+
+func (c *DirichletMultinomialComponent) Value() float64 {
+	return c.value
+}
+
+func (c *DirichletMultinomialComponent) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 || roundedVal > c.DirichletMultinomial.N {
+		return &OutOfBoundsErr{fmt.Sprintf("A Dirichlet-multinomial count can only take the integers between 0 and %s as values, got %f", formatFloat(c.DirichletMultinomial.N), newValue)}
+	}
+	c.value = roundedVal
+
+	return nil
+}
+
+func (c *DirichletMultinomialComponent) String() string {
+	return fmt.Sprintf("%s ~ %s[%d]", c.name, c.DirichletMultinomial.distribution(), c.K)
+}
@@ -0,0 +1,24 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDirichletMultinomialLogProb(t *testing.T) {
+	alpha := []Var{NewConstant(1), NewConstant(2), NewConstant(3)}
+	d := NewDirichletMultinomial("n", 4, alpha, nil)
+	counts := []float64{1, 1, 2}
+	for k, c := range d.Components {
+		c.SetValue(counts[k])
+	}
+	var got float64
+	for _, c := range d.Components {
+		got += c.LogProb()
+	}
+	// N! Γ(A) / Γ(N + A) prod_k Γ(x_k + α_k) / (x_k! Γ(α_k)) with A = 6:
+	// 24 Γ(6) / Γ(10) (Γ(2) / Γ(1)) (Γ(3) / Γ(2)) (Γ(5) / (2 Γ(3)))
+	if want := math.Log(24 * 120 * 2 * 6 / 362880.0); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// DiscreteUniform is a random variable that takes each of the integers
+// between Min and Max, included, with the same probability, e.g. the prior
+// of the location of a changepoint in a time series.
+type DiscreteUniform struct {
+	name  string
+	value float64
+	Min   Var
+	Max   Var
+
+	Src *rand.Rand
+}
+
+// NewDiscreteUniform returns a discrete uniform variable. It panics if the
+// bounds are not integers or if min is greater than max.
+func NewDiscreteUniform(name string, min, max Var, src *rand.Rand) *DiscreteUniform {
+	if err := checkDiscreteUniformBounds(min.Value(), max.Value()); err != nil {
+		log.Panic(err)
+	}
+	defaultValue := math.Round((min.Value() + max.Value()) / 2)
+	newDiscreteUniform := DiscreteUniform{
+		name:  name,
+		value: defaultValue,
+		Min:   min,
+		Max:   max,
+		Src:   src,
+	}
+	return &newDiscreteUniform
+}
+
+// checkDiscreteUniformBounds checks that the bounds are integers and that
+// min does not exceed max.
+func checkDiscreteUniformBounds(min, max float64) error {
+	if min != math.Round(min) || max != math.Round(max) {
+		return fmt.Errorf("the bounds of a discrete uniform variable must be integers, got %f and %f", min, max)
+	}
+	if min > max {
+		return fmt.Errorf("the lower bound of a discrete uniform variable must not exceed the upper bound, got %f and %f", min, max)
+	}
+	return nil
+}
+
+func (d *DiscreteUniform) LogProb() float64 {
+	min, max := d.Min.Value(), d.Max.Value()
+	if d.value < min || d.value > max {
+		return math.Inf(-1)
+	}
+	return -math.Log(max - min + 1)
+}
+
+func (d *DiscreteUniform) CDF(x float64) float64 {
+	min, max := d.Min.Value(), d.Max.Value()
+	switch {
+	case x < min:
+		return 0
+	case x >= max:
+		return 1
+	}
+	return (math.Floor(x) - min + 1) / (max - min + 1)
+}
+
+func (d *DiscreteUniform) Rand() float64 {
+	min, max := d.Min.Value(), d.Max.Value()
+	if err := checkDiscreteUniformBounds(min, max); err != nil {
+		log.Panic(err)
+	}
+	return min + float64(randIntn(d.Src, int(max-min)+1))
+}
+
+func (d *DiscreteUniform) Name() string {
+	return d.name
+}
+
+func (d *DiscreteUniform) Value() float64 {
+	return d.value
+}
+
+func (d *DiscreteUniform) SetValue(newValue float64) error {
+	min, max := d.Min.Value(), d.Max.Value()
+	roundedVal := math.Round(newValue)
+	if roundedVal < min || roundedVal > max {
+		return &OutOfBoundsErr{fmt.Sprintf("DiscreteUniform takes the integers between %s and %s as values, got %f", formatFloat(min), formatFloat(max), newValue)}
+	}
+	d.value = roundedVal
+
+	return nil
+}
+
+func (d *DiscreteUniform) String() string {
+	return fmt.Sprintf("%s ~ DiscreteUniform(Min=%s, Max=%s)", d.name, describe(d.Min), describe(d.Max))
+}
+
+func (d *DiscreteUniform) Mean() float64 {
+	return (d.Min.Value() + d.Max.Value()) / 2
+}
+
+func (d *DiscreteUniform) Variance() float64 {
+	n := d.Max.Value() - d.Min.Value() + 1
+	return (n*n - 1) / 12
+}
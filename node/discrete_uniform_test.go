@@ -0,0 +1,20 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiscreteUniformLogProb(t *testing.T) {
+	d := NewDiscreteUniform("k", NewConstant(1), NewConstant(6), nil)
+	d.SetValue(3.2)
+	if d.Value() != 3 {
+		t.Errorf("the value was rounded to %f, want 3", d.Value())
+	}
+	if got, want := d.LogProb(), -math.Log(6); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+	if err := d.SetValue(7); err == nil {
+		t.Error("accepted a value above the upper bound")
+	}
+}
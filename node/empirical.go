@@ -0,0 +1,70 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// An Empirical variable replaces the prior distribution of a random variable
+// by the distribution of draws obtained elsewhere, typically the posterior
+// samples of a previous fit.
+//
+// The density is a gaussian kernel density estimate of the draws. The value
+// is stored in the wrapped variable so that the nodes that depend on it are
+// left untouched.
+type Empirical struct {
+	RandVar
+	Draws     []float64
+	Bandwidth float64
+
+	Src *rand.Rand
+}
+
+// NewEmpirical wraps a random variable with the empirical distribution of
+// draws. The bandwidth is chosen with Silverman's rule of thumb.
+func NewEmpirical(variable RandVar, draws []float64, src *rand.Rand) *Empirical {
+	n := float64(len(draws))
+	std := stat.StdDev(draws, nil)
+	if std == 0 || math.IsNaN(std) {
+		std = 1
+	}
+	bandwidth := 1.06 * std * math.Pow(n, -0.2)
+
+	newEmpirical := Empirical{
+		RandVar:   variable,
+		Draws:     draws,
+		Bandwidth: bandwidth,
+		Src:       src,
+	}
+	return &newEmpirical
+}
+
+func (e *Empirical) LogProb() float64 {
+	kernel := distuv.Normal{Mu: 0, Sigma: e.Bandwidth}
+	x := e.Value()
+	logprobs := make([]float64, len(e.Draws))
+	for i, draw := range e.Draws {
+		logprobs[i] = kernel.LogProb(x - draw)
+	}
+	return floats.LogSumExp(logprobs) - math.Log(float64(len(e.Draws)))
+}
+
+func (e *Empirical) Rand() float64 {
+	kernel := distuv.Normal{Mu: 0, Sigma: e.Bandwidth, Src: e.Src}
+	var i int
+	if e.Src == nil {
+		i = rand.Intn(len(e.Draws))
+	} else {
+		i = e.Src.Intn(len(e.Draws))
+	}
+	return e.Draws[i] + kernel.Rand()
+}
+
+func (e *Empirical) String() string {
+	return fmt.Sprintf("%s ~ Empirical(%d draws, Bandwidth=%s)", e.Name(), len(e.Draws), formatFloat(e.Bandwidth))
+}
@@ -0,0 +1,74 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Exponential is a random variable that follows an exponential distribution
+// of rate Rate, e.g. the waiting time between events that occur at this rate.
+type Exponential struct {
+	name  string
+	value float64
+	Rate  Var
+
+	Src *rand.Rand
+}
+
+func NewExponential(name string, rate Var, src *rand.Rand) *Exponential {
+	defaultValue := 1 / rate.Value()
+	newExponential := Exponential{
+		name:  name,
+		value: defaultValue,
+		Rate:  rate,
+		Src:   src,
+	}
+	return &newExponential
+}
+
+func (e *Exponential) LogProb() float64 {
+	dist := distuv.Exponential{Rate: e.Rate.Value()}
+	return dist.LogProb(e.value)
+}
+
+func (e *Exponential) CDF(x float64) float64 {
+	dist := distuv.Exponential{Rate: e.Rate.Value()}
+	return dist.CDF(x)
+}
+
+func (e *Exponential) Rand() float64 {
+	dist := distuv.Exponential{Rate: e.Rate.Value(), Src: e.Src}
+	return dist.Rand()
+}
+
+func (e *Exponential) Name() string {
+	return e.name
+}
+
+func (e *Exponential) Value() float64 {
+	return e.value
+}
+
+func (e *Exponential) SetValue(newValue float64) error {
+	if newValue < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("Exponential is defined on [0,+inf), got value %f", newValue)}
+	}
+	e.value = newValue
+
+	return nil
+}
+
+func (e *Exponential) String() string {
+	return fmt.Sprintf("%s ~ Exponential(Rate=%s)", e.name, describe(e.Rate))
+}
+
+func (e *Exponential) Mean() float64 {
+	return 1 / e.Rate.Value()
+}
+
+func (e *Exponential) Variance() float64 {
+	rate := e.Rate.Value()
+	return 1 / (rate * rate)
+}
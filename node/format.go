@@ -0,0 +1,23 @@
+package node
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// describe returns how a parameter appears in the description of a node: the
+// name of random variables, the expression of deterministic nodes and the
+// value of anything else.
+func describe(v Var) string {
+	switch p := v.(type) {
+	case RandVar:
+		return p.Name()
+	case fmt.Stringer:
+		return p.String()
+	}
+	return formatFloat(v.Value())
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Gamma is a random variable that follows a Gamma distribution with shape
+// Alpha and rate Beta.
+type Gamma struct {
+	name  string
+	value float64
+	Alpha Var
+	Beta  Var
+
+	Src *rand.Rand
+}
+
+func NewGamma(name string, alpha, beta Var, src *rand.Rand) *Gamma {
+	defaultValue := alpha.Value() / beta.Value()
+	newGamma := Gamma{
+		name:  name,
+		value: defaultValue,
+		Alpha: alpha,
+		Beta:  beta,
+		Src:   src,
+	}
+	return &newGamma
+}
+
+func (g *Gamma) LogProb() float64 {
+	dist := distuv.Gamma{Alpha: g.Alpha.Value(), Beta: g.Beta.Value()}
+	return dist.LogProb(g.value)
+}
+
+func (g *Gamma) CDF(x float64) float64 {
+	dist := distuv.Gamma{Alpha: g.Alpha.Value(), Beta: g.Beta.Value()}
+	return dist.CDF(x)
+}
+
+func (g *Gamma) Rand() float64 {
+	dist := distuv.Gamma{Alpha: g.Alpha.Value(), Beta: g.Beta.Value(), Src: g.Src}
+	return dist.Rand()
+}
+
+func (g *Gamma) Name() string {
+	return g.name
+}
+
+func (g *Gamma) Value() float64 {
+	return g.value
+}
+
+func (g *Gamma) SetValue(newValue float64) error {
+	if newValue <= 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("Gamma is defined on (0,+inf), got value %f", newValue)}
+	}
+	g.value = newValue
+
+	return nil
+}
+
+func (g *Gamma) String() string {
+	return fmt.Sprintf("%s ~ Gamma(Alpha=%s, Beta=%s)", g.name, describe(g.Alpha), describe(g.Beta))
+}
+
+func (g *Gamma) Mean() float64 {
+	return g.Alpha.Value() / g.Beta.Value()
+}
+
+func (g *Gamma) Variance() float64 {
+	beta := g.Beta.Value()
+	return g.Alpha.Value() / (beta * beta)
+}
@@ -0,0 +1,91 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Geometric is a random variable that counts the number of failures before
+// the first success in a sequence of independent trials that succeed with
+// probability P. It takes the values 0, 1, 2, ...
+type Geometric struct {
+	name  string
+	value float64
+	P     Var
+
+	Src *rand.Rand
+}
+
+func NewGeometric(name string, p Var, src *rand.Rand) *Geometric {
+	defaultValue := 0.0
+	newGeometric := Geometric{
+		name:  name,
+		value: defaultValue,
+		P:     p,
+		Src:   src,
+	}
+	return &newGeometric
+}
+
+func (g *Geometric) LogProb() float64 {
+	p := g.P.Value()
+	if p <= 0 || p > 1 {
+		return math.Inf(-1)
+	}
+	if p == 1 {
+		if g.value == 0 {
+			return 0
+		}
+		return math.Inf(-1)
+	}
+	return math.Log(p) + g.value*math.Log1p(-p)
+}
+
+func (g *Geometric) CDF(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	return -math.Expm1((math.Floor(x) + 1) * math.Log1p(-g.P.Value()))
+}
+
+// Rand rounds down an exponential variable of rate -log(1 - P), which is
+// geometrically distributed.
+func (g *Geometric) Rand() float64 {
+	dist := distuv.Exponential{Rate: -math.Log1p(-g.P.Value()), Src: g.Src}
+	return math.Floor(dist.Rand())
+}
+
+func (g *Geometric) Name() string {
+	return g.name
+}
+
+func (g *Geometric) Value() float64 {
+	return g.value
+}
+
+func (g *Geometric) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("A geometric random variable can only take positive integers as values, got %f", newValue)}
+	}
+	g.value = roundedVal
+
+	return nil
+}
+
+func (g *Geometric) String() string {
+	return fmt.Sprintf("%s ~ Geometric(P=%s)", g.name, describe(g.P))
+}
+
+func (g *Geometric) Mean() float64 {
+	p := g.P.Value()
+	return (1 - p) / p
+}
+
+func (g *Geometric) Variance() float64 {
+	p := g.P.Value()
+	return (1 - p) / (p * p)
+}
@@ -0,0 +1,32 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeometricLogProb(t *testing.T) {
+	cases := []struct{ k, p, want float64 }{
+		{2, 0.3, math.Log(0.3 * 0.7 * 0.7)},
+		{0, 1, 0},
+		{1, 1, math.Inf(-1)},
+		{1, 0, math.Inf(-1)},
+	}
+	for _, c := range cases {
+		g := NewGeometric("k", NewConstant(c.p), nil)
+		g.SetValue(c.k)
+		if got := g.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("k=%g, p=%g: got %f, want %f", c.k, c.p, got, c.want)
+		}
+	}
+
+	g := NewGeometric("k", NewConstant(0.3), nil)
+	var mass float64
+	for k := 0; k < 200; k++ {
+		g.SetValue(float64(k))
+		mass += math.Exp(g.LogProb())
+	}
+	if math.Abs(mass-1) > 1e-12 {
+		t.Errorf("the probabilities sum to %f", mass)
+	}
+}
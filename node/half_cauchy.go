@@ -0,0 +1,73 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// HalfCauchy is a random variable whose value is the absolute value of a
+// Cauchy variable of location 0 and scale Scale. Its heavy tail makes it a
+// weakly informative prior for scale parameters that still allows large
+// values:
+//
+// "Prior distributions for variance parameters in hierarchical models" (Gelman 2006)
+// https://doi.org/10.1214/06-BA117A
+type HalfCauchy struct {
+	name  string
+	value float64
+	Scale Var
+
+	Src *rand.Rand
+}
+
+func NewHalfCauchy(name string, scale Var, src *rand.Rand) *HalfCauchy {
+	defaultValue := scale.Value()
+	newHalfCauchy := HalfCauchy{
+		name:  name,
+		value: defaultValue,
+		Scale: scale,
+		Src:   src,
+	}
+	return &newHalfCauchy
+}
+
+func (h *HalfCauchy) LogProb() float64 {
+	scale := h.Scale.Value()
+	z := h.value / scale
+	return math.Log(2/(math.Pi*scale)) - math.Log1p(z*z)
+}
+
+func (h *HalfCauchy) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return 2 * math.Atan(x/h.Scale.Value()) / math.Pi
+}
+
+func (h *HalfCauchy) Rand() float64 {
+	scale := h.Scale.Value()
+	return scale * math.Tan(math.Pi/2*randFloat64(h.Src))
+}
+
+func (h *HalfCauchy) Name() string {
+	return h.name
+}
+
+func (h *HalfCauchy) Value() float64 {
+	return h.value
+}
+
+func (h *HalfCauchy) SetValue(newValue float64) error {
+	if newValue < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("HalfCauchy is defined on [0,+inf), got value %f", newValue)}
+	}
+	h.value = newValue
+
+	return nil
+}
+
+func (h *HalfCauchy) String() string {
+	return fmt.Sprintf("%s ~ HalfCauchy(Scale=%s)", h.name, describe(h.Scale))
+}
@@ -0,0 +1,20 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHalfCauchyLogProb(t *testing.T) {
+	h := NewHalfCauchy("s", NewConstant(2), nil)
+	h.SetValue(1)
+	// 2 / (pi scale (1 + (x / scale)^2))
+	if got, want := h.LogProb(), math.Log(2/(math.Pi*2*1.25)); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+
+	// The tails are heavy: the mass beyond 1e4 is about 2 scale / (pi 1e4).
+	if mass := integrate(h, 0, 1e4); math.Abs(mass-1+4/(math.Pi*1e4)) > 1e-6 {
+		t.Errorf("the density integrates to %f", mass)
+	}
+}
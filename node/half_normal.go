@@ -0,0 +1,78 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// HalfNormal is a random variable whose value is the absolute value of a
+// normal variable of mean 0 and standard deviation Sigma. It is a common
+// weakly informative prior for scale parameters.
+type HalfNormal struct {
+	name  string
+	value float64
+	Sigma Var
+
+	Src *rand.Rand
+}
+
+func NewHalfNormal(name string, sigma Var, src *rand.Rand) *HalfNormal {
+	defaultValue := sigma.Value()
+	newHalfNormal := HalfNormal{
+		name:  name,
+		value: defaultValue,
+		Sigma: sigma,
+		Src:   src,
+	}
+	return &newHalfNormal
+}
+
+func (h *HalfNormal) LogProb() float64 {
+	dist := distuv.Normal{Mu: 0, Sigma: h.Sigma.Value()}
+	return math.Ln2 + dist.LogProb(h.value)
+}
+
+func (h *HalfNormal) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return math.Erf(x / (math.Sqrt2 * h.Sigma.Value()))
+}
+
+func (h *HalfNormal) Rand() float64 {
+	dist := distuv.Normal{Mu: 0, Sigma: h.Sigma.Value(), Src: h.Src}
+	return math.Abs(dist.Rand())
+}
+
+func (h *HalfNormal) Name() string {
+	return h.name
+}
+
+func (h *HalfNormal) Value() float64 {
+	return h.value
+}
+
+func (h *HalfNormal) SetValue(newValue float64) error {
+	if newValue < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("HalfNormal is defined on [0,+inf), got value %f", newValue)}
+	}
+	h.value = newValue
+
+	return nil
+}
+
+func (h *HalfNormal) String() string {
+	return fmt.Sprintf("%s ~ HalfNormal(Sigma=%s)", h.name, describe(h.Sigma))
+}
+
+func (h *HalfNormal) Mean() float64 {
+	return h.Sigma.Value() * math.Sqrt(2/math.Pi)
+}
+
+func (h *HalfNormal) Variance() float64 {
+	sigma := h.Sigma.Value()
+	return sigma * sigma * (1 - 2/math.Pi)
+}
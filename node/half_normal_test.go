@@ -0,0 +1,18 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHalfNormalLogProb(t *testing.T) {
+	h := NewHalfNormal("s", NewConstant(2), nil)
+	h.SetValue(1)
+	// 2 / (sigma sqrt(2 pi)) exp(-x^2 / (2 sigma^2))
+	if got, want := h.LogProb(), math.Log(2/(2*math.Sqrt(2*math.Pi)))-0.125; !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+	if mass := integrate(h, 0, 40); math.Abs(mass-1) > 1e-6 {
+		t.Errorf("the density integrates to %f", mass)
+	}
+}
@@ -0,0 +1,104 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// The Huber distribution is a normal distribution whose tails are replaced by
+// exponential tails beyond Delta standard deviations. Its log-density is the
+// opposite of the Huber loss of the residual, so using it as a likelihood
+// yields a regression that is robust to outliers.
+//
+// If we note z = (x - mu) / sigma, the density is proportional to:
+//
+// exp(-z^2 / 2)                      if |z| <= delta
+// exp(-delta * |z| + delta^2 / 2)   otherwise
+type Huber struct {
+	name  string
+	value float64
+	Mu    Var
+	Sigma Var
+	Delta float64 // Delta > 0 is the threshold, in units of Sigma
+
+	Src *rand.Rand
+}
+
+func NewHuber(name string, mu, sigma Var, delta float64, src *rand.Rand) *Huber {
+	defaultValue := mu.Value()
+	newHuber := Huber{
+		name:  name,
+		value: defaultValue,
+		Mu:    mu,
+		Sigma: sigma,
+		Delta: delta,
+		Src:   src,
+	}
+	return &newHuber
+}
+
+// logNormalizer returns the logarithm of the integral of the unnormalized
+// density of the standardized variable, and the log of the mass in the
+// gaussian part.
+func (h *Huber) logNormalizer() (float64, float64) {
+	d := h.Delta
+	std := distuv.UnitNormal
+	center := math.Sqrt(2*math.Pi) * (2*std.CDF(d) - 1)
+	tails := 2 * math.Exp(-d*d/2) / d
+	return math.Log(center + tails), math.Log(center)
+}
+
+func (h *Huber) LogProb() float64 {
+	sigma := h.Sigma.Value()
+	z := math.Abs(h.value-h.Mu.Value()) / sigma
+	var loss float64
+	if z <= h.Delta {
+		loss = z * z / 2
+	} else {
+		loss = h.Delta*z - h.Delta*h.Delta/2
+	}
+	logZ, _ := h.logNormalizer()
+	return -loss - logZ - math.Log(sigma)
+}
+
+func (h *Huber) Rand() float64 {
+	logZ, logCenter := h.logNormalizer()
+	uniform := distuv.Uniform{Min: 0, Max: 1, Src: h.Src}
+
+	var z float64
+	if math.Log(uniform.Rand()) < logCenter-logZ {
+		// Rejection sampling from the truncated standard normal.
+		std := distuv.Normal{Mu: 0, Sigma: 1, Src: h.Src}
+		z = std.Rand()
+		for math.Abs(z) > h.Delta {
+			z = std.Rand()
+		}
+	} else {
+		tail := distuv.Exponential{Rate: h.Delta, Src: h.Src}
+		z = h.Delta + tail.Rand()
+		if uniform.Rand() < 0.5 {
+			z = -z
+		}
+	}
+	return h.Mu.Value() + h.Sigma.Value()*z
+}
+
+func (h *Huber) Name() string {
+	return h.name
+}
+
+func (h *Huber) Value() float64 {
+	return h.value
+}
+
+func (h *Huber) SetValue(newValue float64) error {
+	h.value = newValue
+	return nil
+}
+
+func (h *Huber) String() string {
+	return fmt.Sprintf("%s ~ Huber(Mu=%s, Sigma=%s, Delta=%s)", h.name, describe(h.Mu), describe(h.Sigma), formatFloat(h.Delta))
+}
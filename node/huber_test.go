@@ -0,0 +1,64 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestHuberLogProb(t *testing.T) {
+	h := NewHuber("y", NewConstant(0), NewConstant(2), 1.5, nil)
+	// The normalizing constant is the integral of exp(-loss) over the
+	// quadratic part and the two exponential tails.
+	logZ := math.Log(math.Sqrt(2*math.Pi)*(2*distuv.UnitNormal.CDF(1.5)-1) + 2*math.Exp(-1.125)/1.5)
+	cases := []struct{ x, want float64 }{
+		{1, -0.125 - logZ - math.Log(2)},              // z = 0.5, quadratic
+		{-5, -(1.5*2.5 - 1.125) - logZ - math.Log(2)}, // z = 2.5, linear
+	}
+	for _, c := range cases {
+		h.SetValue(c.x)
+		if got := h.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("x=%g: got %f, want %f", c.x, got, c.want)
+		}
+	}
+
+	if mass := integrate(h, -100, 100); math.Abs(mass-1) > 1e-6 {
+		t.Errorf("the density integrates to %f", mass)
+	}
+}
+
+func TestAsymmetricLaplaceLogProb(t *testing.T) {
+	a := NewAsymmetricLaplace("y", NewConstant(1), NewConstant(2), 0.3, nil)
+	cases := []struct{ x, want float64 }{
+		{3, math.Log(0.21) - math.Log(2) - 0.3},  // u = 1
+		{-1, math.Log(0.21) - math.Log(2) - 0.7}, // u = -1
+	}
+	for _, c := range cases {
+		a.SetValue(c.x)
+		if got := a.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("x=%g: got %f, want %f", c.x, got, c.want)
+		}
+	}
+
+	if mass := integrate(a, -200, 200); math.Abs(mass-1) > 1e-6 {
+		t.Errorf("the density integrates to %f", mass)
+	}
+}
+
+// integrate returns the integral of the density of the variable over
+// [lower, upper] with the trapezoidal rule. It leaves the variable at upper.
+func integrate(v RandVar, lower, upper float64) float64 {
+	const steps = 400000
+	width := (upper - lower) / steps
+	var sum float64
+	for i := 0; i <= steps; i++ {
+		v.SetValue(lower + float64(i)*width)
+		density := math.Exp(v.LogProb())
+		if i == 0 || i == steps {
+			density /= 2
+		}
+		sum += density
+	}
+	return sum * width
+}
@@ -11,3 +11,17 @@ type RandVar interface {
 	LogProb() float64
 	Rand() float64
 }
+
+// A Moments is a random variable whose mean and variance, given the value of
+// its parameters, are known in closed form.
+type Moments interface {
+	Mean() float64
+	Variance() float64
+}
+
+// A CDF is a random variable whose cumulative distribution function, given
+// the value of its parameters, is known in closed form: CDF(x) is the
+// probability that the variable is lower than or equal to x.
+type CDF interface {
+	CDF(x float64) float64
+}
@@ -0,0 +1,72 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// InverseGamma is a random variable whose inverse follows a Gamma
+// distribution of shape Alpha and rate Beta. It is the conjugate prior of the
+// variance of a normal distribution.
+type InverseGamma struct {
+	name  string
+	value float64
+	Alpha Var
+	Beta  Var
+
+	Src *rand.Rand
+}
+
+func NewInverseGamma(name string, alpha, beta Var, src *rand.Rand) *InverseGamma {
+	// The mode is defined for every alpha, unlike the mean.
+	defaultValue := beta.Value() / (alpha.Value() + 1)
+	newInverseGamma := InverseGamma{
+		name:  name,
+		value: defaultValue,
+		Alpha: alpha,
+		Beta:  beta,
+		Src:   src,
+	}
+	return &newInverseGamma
+}
+
+func (g *InverseGamma) LogProb() float64 {
+	dist := distuv.InverseGamma{Alpha: g.Alpha.Value(), Beta: g.Beta.Value()}
+	return dist.LogProb(g.value)
+}
+
+func (g *InverseGamma) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	dist := distuv.InverseGamma{Alpha: g.Alpha.Value(), Beta: g.Beta.Value()}
+	return dist.CDF(x)
+}
+
+func (g *InverseGamma) Rand() float64 {
+	dist := distuv.InverseGamma{Alpha: g.Alpha.Value(), Beta: g.Beta.Value(), Src: g.Src}
+	return dist.Rand()
+}
+
+func (g *InverseGamma) Name() string {
+	return g.name
+}
+
+func (g *InverseGamma) Value() float64 {
+	return g.value
+}
+
+func (g *InverseGamma) SetValue(newValue float64) error {
+	if newValue <= 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("InverseGamma is defined on (0,+inf), got value %f", newValue)}
+	}
+	g.value = newValue
+
+	return nil
+}
+
+func (g *InverseGamma) String() string {
+	return fmt.Sprintf("%s ~ InverseGamma(Alpha=%s, Beta=%s)", g.name, describe(g.Alpha), describe(g.Beta))
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Laplace is a random variable that follows a Laplace (double exponential)
+// distribution of location Mu and scale Scale. As a prior on regression
+// coefficients it yields the Bayesian lasso:
+//
+// "The Bayesian Lasso" (Park & Casella 2008)
+// https://doi.org/10.1198/016214508000000337
+type Laplace struct {
+	name  string
+	value float64
+	Mu    Var
+	Scale Var
+
+	Src *rand.Rand
+}
+
+func NewLaplace(name string, mu, scale Var, src *rand.Rand) *Laplace {
+	defaultValue := mu.Value()
+	newLaplace := Laplace{
+		name:  name,
+		value: defaultValue,
+		Mu:    mu,
+		Scale: scale,
+		Src:   src,
+	}
+	return &newLaplace
+}
+
+func (l *Laplace) LogProb() float64 {
+	dist := distuv.Laplace{Mu: l.Mu.Value(), Scale: l.Scale.Value()}
+	return dist.LogProb(l.value)
+}
+
+func (l *Laplace) CDF(x float64) float64 {
+	dist := distuv.Laplace{Mu: l.Mu.Value(), Scale: l.Scale.Value()}
+	return dist.CDF(x)
+}
+
+func (l *Laplace) Rand() float64 {
+	dist := distuv.Laplace{Mu: l.Mu.Value(), Scale: l.Scale.Value(), Src: l.Src}
+	return dist.Rand()
+}
+
+func (l *Laplace) Name() string {
+	return l.name
+}
+
+func (l *Laplace) Value() float64 {
+	return l.value
+}
+
+func (l *Laplace) SetValue(newValue float64) error {
+	l.value = newValue
+	return nil
+}
+
+func (l *Laplace) String() string {
+	return fmt.Sprintf("%s ~ Laplace(Mu=%s, Scale=%s)", l.name, describe(l.Mu), describe(l.Scale))
+}
+
+func (l *Laplace) Mean() float64 {
+	return l.Mu.Value()
+}
+
+func (l *Laplace) Variance() float64 {
+	scale := l.Scale.Value()
+	return 2 * scale * scale
+}
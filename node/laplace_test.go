@@ -0,0 +1,15 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLaplaceLogProb(t *testing.T) {
+	l := NewLaplace("x", NewConstant(1), NewConstant(2), nil)
+	l.SetValue(4)
+	// exp(-|x - mu| / scale) / (2 scale)
+	if got, want := l.LogProb(), -math.Log(4)-1.5; !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
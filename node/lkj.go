@@ -0,0 +1,147 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+// The LKJ distribution is a distribution over correlation matrices whose
+// density is proportional to det(R)^(Eta - 1). Eta = 1 is uniform over
+// correlation matrices, and larger values of Eta favour weaker correlations.
+// It is the usual prior of the correlations of random effects, the standard
+// deviations having their own prior.
+//
+// Samplers explore unconstrained scalar variables, so the matrix is built
+// from its canonical partial correlations z_ij, for i > j, with the C-vine
+// method: z_ij is the correlation of variables i and j given the variables
+// before j, and it follows a Beta(b_j, b_j) distribution rescaled to (-1, 1)
+// with b_j = Eta + (p - 2 - j) / 2. The Cholesky factor L of the matrix
+// follows from the partial correlations:
+//
+// L_i0 = z_i0
+// L_ij = z_ij * sqrt(1 - sum_{k<j} L_ik^2)
+// L_ii = sqrt(1 - sum_{k<i} L_ik^2)
+//
+// and the matrix R = L L^T follows an LKJ(Eta) distribution.
+//
+// "Generating random correlation matrices based on vines and extended onion
+// method" (Lewandowski, Kurowicka & Joe 2009)
+// https://doi.org/10.1016/j.jmva.2009.04.008
+type LKJCorr struct {
+	name string
+	dim  int
+	Eta  float64
+
+	// Betas contains the partial correlations z_ij rescaled to (0, 1), row
+	// by row.
+	Betas []*Beta
+}
+
+// NewLKJCorr returns a dim×dim LKJ correlation matrix. The Beta variables are
+// named "<name>_z_<i>_<j>".
+func NewLKJCorr(name string, dim int, eta float64, src *rand.Rand) *LKJCorr {
+	if dim < 2 {
+		log.Panicf("the correlation matrix needs at least 2 rows, got %d", dim)
+	}
+	if eta <= 0 {
+		log.Panicf("the LKJ shape must be strictly positive, got %f", eta)
+	}
+	l := &LKJCorr{name: name, dim: dim, Eta: eta}
+	for i := 1; i < dim; i++ {
+		for j := 0; j < i; j++ {
+			b := NewConstant(eta + float64(dim-2-j)/2)
+			l.Betas = append(l.Betas, NewBeta(fmt.Sprintf("%s_z_%d_%d", name, i, j), b, b, src))
+		}
+	}
+	return l
+}
+
+func (l *LKJCorr) Name() string {
+	return l.name
+}
+
+// Dim returns the number of rows and columns of the matrix.
+func (l *LKJCorr) Dim() int {
+	return l.dim
+}
+
+// Cholesky returns the current value of the lower triangular Cholesky factor
+// L of the correlation matrix. Multiplying its rows by standard deviations
+// gives the Cholesky factor of a covariance matrix, which is how correlated
+// random effects are usually built: b = diag(sigma) L u with u standard
+// normal.
+func (l *LKJCorr) Cholesky() *mat.TriDense {
+	factor := mat.NewTriDense(l.dim, mat.Lower, nil)
+	factor.SetTri(0, 0, 1)
+	next := 0
+	for i := 1; i < l.dim; i++ {
+		var sumSquares float64
+		for j := 0; j < i; j++ {
+			z := 2*l.Betas[next].Value() - 1
+			next++
+			entry := z * math.Sqrt(1-sumSquares)
+			factor.SetTri(i, j, entry)
+			sumSquares += entry * entry
+		}
+		factor.SetTri(i, i, math.Sqrt(math.Max(0, 1-sumSquares)))
+	}
+	return factor
+}
+
+// Matrix returns the current value of the correlation matrix.
+func (l *LKJCorr) Matrix() *mat.SymDense {
+	var matrix mat.SymDense
+	matrix.SymOuterK(1, l.Cholesky())
+	return &matrix
+}
+
+// Entry returns the entry (i, j) of the correlation matrix as a variable
+// that can be used as the parameter of other nodes.
+func (l *LKJCorr) Entry(i, j int) Var {
+	l.checkEntry(i, j)
+	return &LKJEntry{LKJCorr: l, I: i, J: j}
+}
+
+// CholeskyEntry returns the entry (i, j) of the Cholesky factor of the
+// correlation matrix as a variable that can be used as the parameter of
+// other nodes.
+func (l *LKJCorr) CholeskyEntry(i, j int) Var {
+	l.checkEntry(i, j)
+	return &LKJEntry{LKJCorr: l, I: i, J: j, Cholesky: true}
+}
+
+func (l *LKJCorr) checkEntry(i, j int) {
+	if i < 0 || j < 0 || i >= l.dim || j >= l.dim {
+		log.Panicf("the entry (%d, %d) is outside of the %d×%d matrix", i, j, l.dim, l.dim)
+	}
+}
+
+func (l *LKJCorr) String() string {
+	return fmt.Sprintf("%s ~ LKJCorr(Dim=%d, Eta=%s)", l.name, l.dim, formatFloat(l.Eta))
+}
+
+// An LKJEntry is an entry of an LKJ correlation matrix or of its Cholesky
+// factor.
+type LKJEntry struct {
+	LKJCorr  *LKJCorr
+	I, J     int
+	Cholesky bool
+}
+
+func (e *LKJEntry) Value() float64 {
+	if e.Cholesky {
+		return e.LKJCorr.Cholesky().At(e.I, e.J)
+	}
+	return e.LKJCorr.Matrix().At(e.I, e.J)
+}
+
+func (e *LKJEntry) String() string {
+	if e.Cholesky {
+		return fmt.Sprintf("chol(%s)[%d,%d]", e.LKJCorr.name, e.I, e.J)
+	}
+	return fmt.Sprintf("%s[%d,%d]", e.LKJCorr.name, e.I, e.J)
+}
@@ -0,0 +1,58 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// TestLKJCorrMatrix checks the C-vine construction on partial correlations
+// of 0.5.
+func TestLKJCorrMatrix(t *testing.T) {
+	l := NewLKJCorr("R", 3, 1, nil)
+	for _, b := range l.Betas {
+		b.SetValue(0.75)
+	}
+	// L = [[1, 0, 0], [0.5, sqrt(0.75), 0], [0.5, 0.5 sqrt(0.75), 0.75]]
+	want := mat.NewSymDense(3, []float64{
+		1, 0.5, 0.5,
+		0.5, 1, 0.625,
+		0.5, 0.625, 1,
+	})
+	if got := l.Matrix(); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("got %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+	if got := l.CholeskyEntry(2, 2).Value(); math.Abs(got-0.75) > 1e-12 {
+		t.Errorf("got the Cholesky entry %f, want 0.75", got)
+	}
+}
+
+// TestLKJCorrMarginals checks the moments of the correlations built from
+// random draws of the partial correlations: their marginal distribution is
+// a Beta(Eta - 1 + p/2, Eta - 1 + p/2) rescaled to (-1, 1), of mean 0 and
+// variance 1 / (2 Eta + p - 1).
+func TestLKJCorrMarginals(t *testing.T) {
+	const dim, eta, numDraws = 3, 2.0, 50000
+	l := NewLKJCorr("R", dim, eta, rand.New(rand.NewSource(1)))
+	draws := make([][]float64, 3)
+	for n := 0; n < numDraws; n++ {
+		for _, b := range l.Betas {
+			b.SetValue(b.Rand())
+		}
+		r := l.Matrix()
+		draws[0] = append(draws[0], r.At(1, 0))
+		draws[1] = append(draws[1], r.At(2, 0))
+		draws[2] = append(draws[2], r.At(2, 1))
+	}
+
+	want := 1 / (2*eta + dim - 1)
+	for i, d := range draws {
+		mean, variance := stat.MeanVariance(d, nil)
+		if math.Abs(mean) > 0.01 || math.Abs(variance-want) > 0.01 {
+			t.Errorf("correlation %d has mean %f and variance %f, want 0 and %f", i, mean, variance, want)
+		}
+	}
+}
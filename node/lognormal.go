@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// LogNormal is a random variable whose logarithm is normally distributed with
+// mean Mu and standard deviation Sigma.
+type LogNormal struct {
+	name  string
+	value float64
+	Mu    Var
+	Sigma Var
+
+	Src *rand.Rand
+}
+
+func NewLogNormal(name string, mu, sigma Var, src *rand.Rand) *LogNormal {
+	defaultValue := math.Exp(mu.Value())
+	newLogNormal := LogNormal{
+		name:  name,
+		value: defaultValue,
+		Mu:    mu,
+		Sigma: sigma,
+		Src:   src,
+	}
+	return &newLogNormal
+}
+
+func (l *LogNormal) LogProb() float64 {
+	dist := distuv.LogNormal{Mu: l.Mu.Value(), Sigma: l.Sigma.Value()}
+	return dist.LogProb(l.value)
+}
+
+func (l *LogNormal) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	dist := distuv.LogNormal{Mu: l.Mu.Value(), Sigma: l.Sigma.Value()}
+	return dist.CDF(x)
+}
+
+func (l *LogNormal) Rand() float64 {
+	dist := distuv.LogNormal{Mu: l.Mu.Value(), Sigma: l.Sigma.Value(), Src: l.Src}
+	return dist.Rand()
+}
+
+func (l *LogNormal) Name() string {
+	return l.name
+}
+
+func (l *LogNormal) Value() float64 {
+	return l.value
+}
+
+func (l *LogNormal) SetValue(newValue float64) error {
+	if newValue <= 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("LogNormal is defined on (0,+inf), got value %f", newValue)}
+	}
+	l.value = newValue
+
+	return nil
+}
+
+func (l *LogNormal) String() string {
+	return fmt.Sprintf("%s ~ LogNormal(Mu=%s, Sigma=%s)", l.name, describe(l.Mu), describe(l.Sigma))
+}
+
+func (l *LogNormal) Mean() float64 {
+	sigma := l.Sigma.Value()
+	return math.Exp(l.Mu.Value() + sigma*sigma/2)
+}
+
+func (l *LogNormal) Variance() float64 {
+	mu, sigma := l.Mu.Value(), l.Sigma.Value()
+	return (math.Exp(sigma*sigma) - 1) * math.Exp(2*mu+sigma*sigma)
+}
@@ -0,0 +1,115 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Mixture is a random variable drawn from one of several distributions, the
+// k-th with probability Weights[k]. The weights are expected to sum to 1,
+// e.g. the components of a Dirichlet vector.
+//
+// The component the value was drawn from is marginalized out of the
+// log-probability:
+//
+// log p(x) = log sum_k Weights[k] * p_k(x)
+//
+// so mixtures can be sampled without discrete label variables. The
+// components are random variables that are not part of the model: their
+// parameters can be variables of the model, and the mixture sets their
+// value to its own to evaluate their log-probability.
+type Mixture struct {
+	name       string
+	value      float64
+	Weights    []Var
+	Components []RandVar
+
+	Src *rand.Rand
+}
+
+func NewMixture(name string, weights []Var, components []RandVar, src *rand.Rand) *Mixture {
+	defaultValue := components[0].Value()
+	newMixture := Mixture{
+		name:       name,
+		value:      defaultValue,
+		Weights:    weights,
+		Components: components,
+		Src:        src,
+	}
+	return &newMixture
+}
+
+// ComponentLogProbs returns, for each component k, the log of the weight of
+// the component plus the log-probability of the value under the component,
+// -Inf when the value is outside of the support of the component.
+// Normalized, they are the probabilities that the value was drawn from each
+// component.
+func (m *Mixture) ComponentLogProbs() []float64 {
+	logProbs := make([]float64, len(m.Components))
+	for k, c := range m.Components {
+		if err := c.SetValue(m.value); err != nil {
+			logProbs[k] = math.Inf(-1)
+			continue
+		}
+		logProbs[k] = math.Log(m.Weights[k].Value()) + c.LogProb()
+	}
+	return logProbs
+}
+
+func (m *Mixture) LogProb() float64 {
+	logProbs := m.ComponentLogProbs()
+	if floats.Max(logProbs) == math.Inf(-1) {
+		return math.Inf(-1)
+	}
+	return floats.LogSumExp(logProbs)
+}
+
+func (m *Mixture) Rand() float64 {
+	weights := make([]float64, len(m.Weights))
+	for k, w := range m.Weights {
+		weights[k] = w.Value()
+	}
+	k := int(distuv.NewCategorical(weights, m.Src).Rand())
+	return m.Components[k].Rand()
+}
+
+func (m *Mixture) Name() string {
+	return m.name
+}
+
+func (m *Mixture) Value() float64 {
+	return m.value
+}
+
+// SetValue sets the value of the mixture, which must be in the support of
+// at least one of the components.
+func (m *Mixture) SetValue(newValue float64) error {
+	var lastErr error
+	for _, c := range m.Components {
+		if err := c.SetValue(newValue); err != nil {
+			lastErr = err
+			continue
+		}
+		m.value = newValue
+		return nil
+	}
+	return &OutOfBoundsErr{fmt.Sprintf("the value %f is outside of the support of every component of the mixture: %v", newValue, lastErr)}
+}
+
+func (m *Mixture) String() string {
+	components := make([]string, len(m.Components))
+	for k, c := range m.Components {
+		description := fmt.Sprint(c)
+		// The components are described by their distribution only.
+		if i := strings.Index(description, " ~ "); i >= 0 {
+			description = description[i+3:]
+		}
+		components[k] = fmt.Sprintf("%s*%s", describe(m.Weights[k]), description)
+	}
+	return fmt.Sprintf("%s ~ %s", m.name, strings.Join(components, " + "))
+}
@@ -0,0 +1,35 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestMixtureLogProb(t *testing.T) {
+	weights := []Var{NewConstant(0.3), NewConstant(0.7)}
+	m := NewMixture("y", weights, []RandVar{
+		NewNormal("y_0", NewConstant(0), NewConstant(1), nil),
+		NewNormal("y_1", NewConstant(2), NewConstant(0.5), nil),
+	}, nil)
+	m.SetValue(1)
+	want := math.Log(0.3*distuv.UnitNormal.Prob(1) + 0.7*distuv.UnitNormal.Prob(-2)/0.5)
+	if got := m.LogProb(); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
+
+// TestMixtureSupport checks that the components whose support excludes the
+// value do not contribute to the mixture.
+func TestMixtureSupport(t *testing.T) {
+	weights := []Var{NewConstant(0.4), NewConstant(0.6)}
+	m := NewMixture("y", weights, []RandVar{
+		NewUniform("y_0", NewConstant(0), NewConstant(1), nil),
+		NewNormal("y_1", NewConstant(0), NewConstant(1), nil),
+	}, nil)
+	m.SetValue(2)
+	if got, want := m.LogProb(), math.Log(0.6*distuv.UnitNormal.Prob(2)); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+}
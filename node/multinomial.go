@@ -0,0 +1,173 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// The Multinomial distribution counts the outcomes of N independent trials
+// that fall in each of K categories, category k having probability P[k].
+// The probabilities can be the components of a Dirichlet vector.
+//
+// The model only holds scalar variables, so the count vector is represented
+// by one variable per category, "<name>_<k>". Their joint probability is
+// factored in a sequence of binomial distributions: the count of category k
+// is the number of successes among the trials that did not fall in the
+// previous categories, with probability P[k] / (P[k] + ... + P[K-1]), and the
+// last count is whatever trials remain.
+//
+// The counts are meant to be observed: a random walk over the counts would
+// almost never propose vectors that sum to N.
+//
+// For more info see: https://en.wikipedia.org/wiki/Multinomial_distribution
+type Multinomial struct {
+	name       string
+	N          float64 // N is the total number of trials > 0
+	P          []Var
+	Components []*MultinomialComponent
+
+	draw []float64 // last vector drawn by Rand
+	Src  *rand.Rand
+}
+
+// NewMultinomial returns a Multinomial vector with one count per category.
+// All the trials initially fall in the last category.
+func NewMultinomial(name string, N float64, p []Var, src *rand.Rand) *Multinomial {
+	m := &Multinomial{name: name, N: N, P: p, Src: src}
+	for k := range p {
+		m.Components = append(m.Components, &MultinomialComponent{
+			name:        fmt.Sprintf("%s_%d", name, k),
+			Multinomial: m,
+			K:           k,
+		})
+	}
+	m.Components[len(p)-1].value = N
+	return m
+}
+
+func (m *Multinomial) Name() string {
+	return m.name
+}
+
+// Values returns the current counts.
+func (m *Multinomial) Values() []float64 {
+	values := make([]float64, len(m.Components))
+	for k, c := range m.Components {
+		values[k] = c.value
+	}
+	return values
+}
+
+// Rand returns a random count vector drawn from the distribution.
+func (m *Multinomial) Rand() []float64 {
+	values := make([]float64, len(m.P))
+	remaining := m.N
+	for k := range values {
+		if k == len(values)-1 {
+			values[k] = remaining
+			break
+		}
+		p := m.conditionalProb(k)
+		if remaining > 0 && p > 0 {
+			dist := distuv.Binomial{N: remaining, P: math.Min(p, 1), Src: m.Src}
+			values[k] = dist.Rand()
+		}
+		remaining -= values[k]
+	}
+	return values
+}
+
+// conditionalProb returns the probability that a trial falls in category k
+// given that it does not fall in the previous categories.
+func (m *Multinomial) conditionalProb(k int) float64 {
+	var rest float64
+	for _, p := range m.P[k:] {
+		rest += p.Value()
+	}
+	return m.P[k].Value() / rest
+}
+
+func (m *Multinomial) String() string {
+	return fmt.Sprintf("%s ~ %s", m.name, m.distribution())
+}
+
+func (m *Multinomial) distribution() string {
+	probs := make([]string, len(m.P))
+	for k, p := range m.P {
+		probs[k] = describe(p)
+	}
+	return fmt.Sprintf("Multinomial(N=%s, P=[%s])", formatFloat(m.N), strings.Join(probs, ", "))
+}
+
+// A MultinomialComponent is the count of one of the categories of a
+// Multinomial vector.
+type MultinomialComponent struct {
+	name        string
+	value       float64
+	Multinomial *Multinomial
+	K           int
+}
+
+// remaining returns the number of trials that do not fall in the categories
+// before this one.
+func (c *MultinomialComponent) remaining() float64 {
+	remaining := c.Multinomial.N
+	for _, previous := range c.Multinomial.Components[:c.K] {
+		remaining -= previous.value
+	}
+	return remaining
+}
+
+// LogProb returns the log-probability of the count given the counts of the
+// previous categories. The sum over the components is the log-probability of
+// the vector.
+func (c *MultinomialComponent) LogProb() float64 {
+	remaining := c.remaining()
+	if c.K == len(c.Multinomial.Components)-1 {
+		if c.value == remaining {
+			return 0
+		}
+		return math.Inf(-1)
+	}
+	if c.value > remaining {
+		return math.Inf(-1)
+	}
+	dist := distuv.Binomial{N: remaining, P: math.Min(c.Multinomial.conditionalProb(c.K), 1)}
+	return dist.LogProb(c.value)
+}
+
+// Rand returns the count of the category in a random vector. The first
+// component draws a new vector and the others return their count in it, so
+// that drawing the components in order yields counts that sum to N.
+func (c *MultinomialComponent) Rand() float64 {
+	if c.K == 0 || c.Multinomial.draw == nil {
+		c.Multinomial.draw = c.Multinomial.Rand()
+	}
+	return c.Multinomial.draw[c.K]
+}
+
+func (c *MultinomialComponent) Name() string {
+	return c.name
+}
+
+func (c *MultinomialComponent) Value() float64 {
+	return c.value
+}
+
+func (c *MultinomialComponent) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 || roundedVal > c.Multinomial.N {
+		return &OutOfBoundsErr{fmt.Sprintf("A multinomial count can only take the integers between 0 and %s as values, got %f", formatFloat(c.Multinomial.N), newValue)}
+	}
+	c.value = roundedVal
+
+	return nil
+}
+
+func (c *MultinomialComponent) String() string {
+	return fmt.Sprintf("%s ~ %s[%d]", c.name, c.Multinomial.distribution(), c.K)
+}
@@ -0,0 +1,33 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMultinomialLogProb(t *testing.T) {
+	p := []Var{NewConstant(0.2), NewConstant(0.3), NewConstant(0.5)}
+	m := NewMultinomial("n", 4, p, nil)
+	counts := []float64{1, 1, 2}
+	// 4! / (1! 1! 2!) 0.2 0.3 0.5^2
+	if got, want := multinomialLogProb(m.Components, counts), math.Log(12*0.2*0.3*0.25); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+	// The counts must sum to N.
+	if got := multinomialLogProb(m.Components, []float64{1, 1, 1}); !math.IsInf(got, -1) {
+		t.Errorf("got %f for counts that do not sum to N, want -Inf", got)
+	}
+}
+
+// multinomialLogProb sets the counts and returns the sum of the
+// log-probabilities of the components.
+func multinomialLogProb(components []*MultinomialComponent, counts []float64) float64 {
+	var logProb float64
+	for k, c := range components {
+		c.SetValue(counts[k])
+	}
+	for _, c := range components {
+		logProb += c.LogProb()
+	}
+	return logProb
+}
@@ -0,0 +1,134 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// NegativeBinomial is a random variable that counts the number of failures
+// before the R-th success in a sequence of independent trials that succeed
+// with probability P. R does not need to be an integer.
+//
+// It is mostly used as an over-dispersed alternative to the Poisson
+// distribution, parameterized by its mean mu and a dispersion alpha, in
+// which case R = alpha and P = alpha / (alpha + mu); see
+// NewNegativeBinomialMean. The variance is then mu + mu^2 / alpha, and the
+// distribution tends to a Poisson of rate mu when alpha grows.
+//
+// For more info see: https://en.wikipedia.org/wiki/Negative_binomial_distribution
+type NegativeBinomial struct {
+	name  string
+	value float64
+	R     Var
+	P     Var
+
+	Src *rand.Rand
+}
+
+func NewNegativeBinomial(name string, r, p Var, src *rand.Rand) *NegativeBinomial {
+	defaultValue := 0.0
+	newNegativeBinomial := NegativeBinomial{
+		name:  name,
+		value: defaultValue,
+		R:     r,
+		P:     p,
+		Src:   src,
+	}
+	return &newNegativeBinomial
+}
+
+// NewNegativeBinomialMean returns a negative binomial variable of mean mu and
+// dispersion alpha.
+func NewNegativeBinomialMean(name string, mu, alpha Var, src *rand.Rand) *NegativeBinomial {
+	return NewNegativeBinomial(name, alpha, &NegativeBinomialPGate{Mu: mu, Alpha: alpha}, src)
+}
+
+func (n *NegativeBinomial) LogProb() float64 {
+	return negativeBinomialLogProb(n.value, n.R.Value(), n.P.Value())
+}
+
+// Rand draws the count from a Poisson distribution whose rate follows a
+// Gamma distribution of shape R and rate P / (1 - P).
+func (n *NegativeBinomial) Rand() float64 {
+	return negativeBinomialRand(n.R.Value(), n.P.Value(), n.Src)
+}
+
+func (n *NegativeBinomial) Name() string {
+	return n.name
+}
+
+func (n *NegativeBinomial) Value() float64 {
+	return n.value
+}
+
+func (n *NegativeBinomial) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("A negative binomial random variable can only take positive integers as values, got %f", newValue)}
+	}
+	n.value = roundedVal
+
+	return nil
+}
+
+func (n *NegativeBinomial) String() string {
+	if g, ok := n.P.(*NegativeBinomialPGate); ok {
+		return fmt.Sprintf("%s ~ NegativeBinomial(Mu=%s, Alpha=%s)", n.name, describe(g.Mu), describe(g.Alpha))
+	}
+	return fmt.Sprintf("%s ~ NegativeBinomial(R=%s, P=%s)", n.name, describe(n.R), describe(n.P))
+}
+
+func (n *NegativeBinomial) Mean() float64 {
+	r, p := n.R.Value(), n.P.Value()
+	return r * (1 - p) / p
+}
+
+func (n *NegativeBinomial) Variance() float64 {
+	r, p := n.R.Value(), n.P.Value()
+	return r * (1 - p) / (p * p)
+}
+
+// The NegativeBinomialPGate is the success probability alpha / (alpha + mu)
+// of a negative binomial distribution of mean Mu and dispersion Alpha.
+type NegativeBinomialPGate struct {
+	Mu    Var
+	Alpha Var
+}
+
+func (g *NegativeBinomialPGate) Value() float64 {
+	alpha := g.Alpha.Value()
+	return alpha / (alpha + g.Mu.Value())
+}
+
+func (g *NegativeBinomialPGate) String() string {
+	return fmt.Sprintf("(%s / (%s + %s))", describe(g.Alpha), describe(g.Alpha), describe(g.Mu))
+}
+
+func negativeBinomialLogProb(k, r, p float64) float64 {
+	if r <= 0 || p <= 0 || p > 1 {
+		return math.Inf(-1)
+	}
+	// Every trial succeeds: there is no failure.
+	if p == 1 {
+		if k == 0 {
+			return 0
+		}
+		return math.Inf(-1)
+	}
+	lgk, _ := math.Lgamma(k + r)
+	lgf, _ := math.Lgamma(k + 1)
+	lgr, _ := math.Lgamma(r)
+	return lgk - lgf - lgr + r*math.Log(p) + k*math.Log1p(-p)
+}
+
+func negativeBinomialRand(r, p float64, src *rand.Rand) float64 {
+	if p == 1 {
+		return 0
+	}
+	rate := distuv.Gamma{Alpha: r, Beta: p / (1 - p), Src: src}
+	dist := distuv.Poisson{Lambda: rate.Rand(), Src: src}
+	return dist.Rand()
+}
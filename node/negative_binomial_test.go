@@ -0,0 +1,43 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNegativeBinomialLogProb(t *testing.T) {
+	cases := []struct {
+		k, r, p float64
+		want    float64
+	}{
+		// C(4, 3) 0.5^2 0.5^3
+		{3, 2, 0.5, math.Log(0.125)},
+		// 0.3^2.5
+		{0, 2.5, 0.3, 2.5 * math.Log(0.3)},
+		{0, 2, 1, 0},
+		{1, 2, 1, math.Inf(-1)},
+		{1, 2, 0, math.Inf(-1)},
+		{1, 0, 0.5, math.Inf(-1)},
+	}
+	for _, c := range cases {
+		n := NewNegativeBinomial("k", NewConstant(c.r), NewConstant(c.p), nil)
+		n.SetValue(c.k)
+		if got := n.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("k=%g, r=%g, p=%g: got %f, want %f", c.k, c.r, c.p, got, c.want)
+		}
+	}
+
+	n := NewNegativeBinomial("k", NewConstant(2), NewConstant(1), nil)
+	if x := n.Rand(); x != 0 {
+		t.Errorf("drew %f with a success probability of 1, want 0", x)
+	}
+}
+
+// closeTo returns whether two log-probabilities are equal up to rounding
+// errors, or both infinite with the same sign.
+func closeTo(a, b float64) bool {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
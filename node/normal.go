@@ -1,6 +1,8 @@
 package node
 
 import (
+	"fmt"
+
 	"golang.org/x/exp/rand"
 	"gonum.org/v1/gonum/stat/distuv"
 )
@@ -31,6 +33,11 @@ func (n *Normal) LogProb() float64 {
 	return dist.LogProb(n.value)
 }
 
+func (n *Normal) CDF(x float64) float64 {
+	dist := distuv.Normal{Mu: n.Mu.Value(), Sigma: n.Sigma.Value()}
+	return dist.CDF(x)
+}
+
 func (n *Normal) Rand() float64 {
 	dist := distuv.Normal{Mu: n.Mu.Value(), Sigma: n.Sigma.Value(), Src: n.Src}
 	return dist.Rand()
@@ -48,3 +55,16 @@ func (n *Normal) SetValue(newValue float64) error {
 	n.value = newValue
 	return nil
 }
+
+func (n *Normal) String() string {
+	return fmt.Sprintf("%s ~ Normal(Mu=%s, Sigma=%s)", n.name, describe(n.Mu), describe(n.Sigma))
+}
+
+func (n *Normal) Mean() float64 {
+	return n.Mu.Value()
+}
+
+func (n *Normal) Variance() float64 {
+	sigma := n.Sigma.Value()
+	return sigma * sigma
+}
@@ -0,0 +1,88 @@
+package node
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// NormalMixture is a random variable that follows a finite mixture of normal
+// distributions: with probability Weights[k] it is drawn from the normal
+// distribution of mean Mus[k] and standard deviation Sigmas[k]. The component
+// is marginalized out of the log-probability, which keeps the variable
+// continuous. The weights are expected to sum to 1, e.g. the components of a
+// Dirichlet vector. See Mixture for components of any distribution.
+type NormalMixture struct {
+	name    string
+	value   float64
+	Weights []Var
+	Mus     []Var
+	Sigmas  []Var
+
+	Src *rand.Rand
+}
+
+func NewNormalMixture(name string, weights, mus, sigmas []Var, src *rand.Rand) *NormalMixture {
+	defaultValue := 0.0
+	newNormalMixture := NormalMixture{
+		name:    name,
+		value:   defaultValue,
+		Weights: weights,
+		Mus:     mus,
+		Sigmas:  sigmas,
+		Src:     src,
+	}
+	return &newNormalMixture
+}
+
+// ComponentLogProbs returns, for each component k, the log of the weight of
+// the component plus the log-probability of the value under the component.
+// Normalized, they are the probabilities that the value was drawn from each
+// component.
+func (n *NormalMixture) ComponentLogProbs() []float64 {
+	logProbs := make([]float64, len(n.Weights))
+	for k := range logProbs {
+		dist := distuv.Normal{Mu: n.Mus[k].Value(), Sigma: n.Sigmas[k].Value()}
+		logProbs[k] = math.Log(n.Weights[k].Value()) + dist.LogProb(n.value)
+	}
+	return logProbs
+}
+
+func (n *NormalMixture) LogProb() float64 {
+	return floats.LogSumExp(n.ComponentLogProbs())
+}
+
+func (n *NormalMixture) Rand() float64 {
+	weights := make([]float64, len(n.Weights))
+	for k, w := range n.Weights {
+		weights[k] = w.Value()
+	}
+	k := int(distuv.NewCategorical(weights, n.Src).Rand())
+	dist := distuv.Normal{Mu: n.Mus[k].Value(), Sigma: n.Sigmas[k].Value(), Src: n.Src}
+	return dist.Rand()
+}
+
+func (n *NormalMixture) Name() string {
+	return n.name
+}
+
+func (n *NormalMixture) Value() float64 {
+	return n.value
+}
+
+func (n *NormalMixture) SetValue(newValue float64) error {
+	n.value = newValue
+	return nil
+}
+
+func (n *NormalMixture) String() string {
+	components := make([]string, len(n.Weights))
+	for k := range components {
+		components[k] = fmt.Sprintf("%s*Normal(Mu=%s, Sigma=%s)", describe(n.Weights[k]), describe(n.Mus[k]), describe(n.Sigmas[k]))
+	}
+	return fmt.Sprintf("%s ~ %s", n.name, strings.Join(components, " + "))
+}
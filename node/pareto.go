@@ -0,0 +1,68 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Pareto is a random variable that follows a Pareto (power law) distribution
+// of scale Xm, the minimum value of the variable, and shape Alpha.
+type Pareto struct {
+	name  string
+	value float64
+	Xm    Var
+	Alpha Var
+
+	Src *rand.Rand
+}
+
+func NewPareto(name string, xm, alpha Var, src *rand.Rand) *Pareto {
+	defaultValue := xm.Value()
+	newPareto := Pareto{
+		name:  name,
+		value: defaultValue,
+		Xm:    xm,
+		Alpha: alpha,
+		Src:   src,
+	}
+	return &newPareto
+}
+
+func (p *Pareto) LogProb() float64 {
+	dist := distuv.Pareto{Xm: p.Xm.Value(), Alpha: p.Alpha.Value()}
+	return dist.LogProb(p.value)
+}
+
+func (p *Pareto) CDF(x float64) float64 {
+	dist := distuv.Pareto{Xm: p.Xm.Value(), Alpha: p.Alpha.Value()}
+	return dist.CDF(x)
+}
+
+func (p *Pareto) Rand() float64 {
+	dist := distuv.Pareto{Xm: p.Xm.Value(), Alpha: p.Alpha.Value(), Src: p.Src}
+	return dist.Rand()
+}
+
+func (p *Pareto) Name() string {
+	return p.name
+}
+
+func (p *Pareto) Value() float64 {
+	return p.value
+}
+
+func (p *Pareto) SetValue(newValue float64) error {
+	xm := p.Xm.Value()
+	if newValue < xm {
+		return &OutOfBoundsErr{fmt.Sprintf("Pareto is defined on [%f,+inf), got value %f", xm, newValue)}
+	}
+	p.value = newValue
+
+	return nil
+}
+
+func (p *Pareto) String() string {
+	return fmt.Sprintf("%s ~ Pareto(Xm=%s, Alpha=%s)", p.name, describe(p.Xm), describe(p.Alpha))
+}
@@ -0,0 +1,18 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParetoLogProb(t *testing.T) {
+	p := NewPareto("x", NewConstant(1), NewConstant(3), nil)
+	p.SetValue(2)
+	// alpha xm^alpha / x^(alpha + 1)
+	if got, want := p.LogProb(), math.Log(3.0/16); !closeTo(got, want) {
+		t.Errorf("got %f, want %f", got, want)
+	}
+	if err := p.SetValue(0.5); err == nil {
+		t.Error("accepted a value below the scale")
+	}
+}
@@ -0,0 +1,75 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Poisson is a random variable that counts the number of events that occur
+// at a rate Lambda.
+type Poisson struct {
+	name   string
+	value  float64
+	Lambda Var
+
+	Src *rand.Rand
+}
+
+func NewPoisson(name string, lambda Var, src *rand.Rand) *Poisson {
+	defaultValue := math.Round(lambda.Value())
+	newPoisson := Poisson{
+		name:   name,
+		value:  defaultValue,
+		Lambda: lambda,
+		Src:    src,
+	}
+	return &newPoisson
+}
+
+func (p *Poisson) LogProb() float64 {
+	dist := distuv.Poisson{Lambda: p.Lambda.Value()}
+	return dist.LogProb(p.value)
+}
+
+func (p *Poisson) CDF(x float64) float64 {
+	dist := distuv.Poisson{Lambda: p.Lambda.Value()}
+	return dist.CDF(x)
+}
+
+func (p *Poisson) Rand() float64 {
+	dist := distuv.Poisson{Lambda: p.Lambda.Value(), Src: p.Src}
+	return dist.Rand()
+}
+
+func (p *Poisson) Name() string {
+	return p.name
+}
+
+func (p *Poisson) Value() float64 {
+	return p.value
+}
+
+func (p *Poisson) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("A Poisson-distributed random variable can only take positive integers as values, got %f", newValue)}
+	}
+	p.value = roundedVal
+
+	return nil
+}
+
+func (p *Poisson) String() string {
+	return fmt.Sprintf("%s ~ Poisson(Lambda=%s)", p.name, describe(p.Lambda))
+}
+
+func (p *Poisson) Mean() float64 {
+	return p.Lambda.Value()
+}
+
+func (p *Poisson) Variance() float64 {
+	return p.Lambda.Value()
+}
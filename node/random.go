@@ -0,0 +1,21 @@
+package node
+
+import "golang.org/x/exp/rand"
+
+// randFloat64 returns a number drawn uniformly in [0, 1) from src, or from
+// the global source when src is nil, as the distuv distributions do.
+func randFloat64(src *rand.Rand) float64 {
+	if src == nil {
+		return rand.Float64()
+	}
+	return src.Float64()
+}
+
+// randIntn returns an integer drawn uniformly in [0, n) from src, or from
+// the global source when src is nil.
+func randIntn(src *rand.Rand, n int) int {
+	if src == nil {
+		return rand.Intn(n)
+	}
+	return src.Intn(n)
+}
@@ -0,0 +1,18 @@
+package node
+
+import "testing"
+
+// TestRandNilSource checks that nodes built without a source of random
+// numbers draw from the global source.
+func TestRandNilSource(t *testing.T) {
+	one := NewConstant(1)
+	for _, v := range []RandVar{
+		NewHalfCauchy("h", one, nil),
+		NewCauchy("c", one, one, nil),
+		NewDiscreteUniform("d", one, NewConstant(3), nil),
+	} {
+		if x := v.Rand(); x != x {
+			t.Errorf("%s drew NaN", v.Name())
+		}
+	}
+}
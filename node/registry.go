@@ -0,0 +1,194 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/rand"
+)
+
+// A Factory builds a random variable of a given kind from its name, its
+// parameters and a source of random numbers. It returns an error when the
+// parameters do not suit the distribution, e.g. when there are too few.
+type Factory func(name string, params []Var, src *rand.Rand) (RandVar, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a kind of random variable available by name, so that
+// packages outside of gmc can provide distributions that models build like
+// the built-in ones. It is meant to be called from the init function of the
+// package that provides the distribution, and panics if the kind is already
+// registered or the factory is nil.
+func Register(kind string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		log.Panicf("the factory of %s is nil", kind)
+	}
+	if _, taken := registry[kind]; taken {
+		log.Panicf("the kind is already registered: %s", kind)
+	}
+	registry[kind] = factory
+}
+
+// Lookup returns the factory registered for a kind of random variable.
+func Lookup(kind string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[kind]
+	return factory, ok
+}
+
+// Kinds returns the registered kinds of random variables in alphabetical
+// order.
+func Kinds() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	kinds := make([]string, 0, len(registry))
+	for kind := range registry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// The built-in distributions are registered under the name they have in the
+// description of the nodes. Parameters that are numbers rather than
+// variables, such as the number of trials of a Binomial, take the value of
+// the corresponding parameter when the variable is built.
+func init() {
+	Register("Normal", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewNormal(name, p[0], p[1], src)
+	}))
+	Register("Beta", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewBeta(name, p[0], p[1], src)
+	}))
+	Register("Bernoulli", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewBernoulli(name, p[0], src)
+	}))
+	Register("Binomial", checkedArity(2, checkTrials, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewBinomial(name, p[0].Value(), p[1], src)
+	}))
+	Register("BetaBinomial", checkedArity(3, checkTrials, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewBetaBinomial(name, p[0].Value(), p[1], p[2], src)
+	}))
+	Register("Huber", checkedArity(3, func(p []Var) error {
+		if delta := p[2].Value(); delta <= 0 {
+			return fmt.Errorf("the Huber threshold must be > 0, got %f", delta)
+		}
+		return nil
+	}, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewHuber(name, p[0], p[1], p[2].Value(), src)
+	}))
+	Register("AsymmetricLaplace", checkedArity(3, func(p []Var) error {
+		if tau := p[2].Value(); tau <= 0 || tau >= 1 {
+			return fmt.Errorf("the quantile must be in (0,1), got %f", tau)
+		}
+		return nil
+	}, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewAsymmetricLaplace(name, p[0], p[1], p[2].Value(), src)
+	}))
+	Register("Gamma", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewGamma(name, p[0], p[1], src)
+	}))
+	Register("Categorical", func(name string, p []Var, src *rand.Rand) (RandVar, error) {
+		if len(p) < 2 {
+			return nil, fmt.Errorf("needed at least 2 parameters, got %d", len(p))
+		}
+		return NewCategorical(name, p, src), nil
+	})
+	Register("Poisson", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewPoisson(name, p[0], src)
+	}))
+	Register("Exponential", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewExponential(name, p[0], src)
+	}))
+	Register("Uniform", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewUniform(name, p[0], p[1], src)
+	}))
+	Register("StudentT", fixedArity(3, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewStudentT(name, p[0], p[1], p[2], src)
+	}))
+	Register("HalfNormal", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewHalfNormal(name, p[0], src)
+	}))
+	Register("HalfCauchy", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewHalfCauchy(name, p[0], src)
+	}))
+	Register("LogNormal", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewLogNormal(name, p[0], p[1], src)
+	}))
+	Register("Cauchy", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewCauchy(name, p[0], p[1], src)
+	}))
+	Register("Laplace", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewLaplace(name, p[0], p[1], src)
+	}))
+	Register("Weibull", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewWeibull(name, p[0], p[1], src)
+	}))
+	Register("InverseGamma", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewInverseGamma(name, p[0], p[1], src)
+	}))
+	Register("ChiSquared", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewChiSquared(name, p[0], src)
+	}))
+	Register("Pareto", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewPareto(name, p[0], p[1], src)
+	}))
+	Register("NegativeBinomial", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewNegativeBinomialMean(name, p[0], p[1], src)
+	}))
+	Register("Geometric", fixedArity(1, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewGeometric(name, p[0], src)
+	}))
+	Register("ZeroInflatedPoisson", fixedArity(2, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewZeroInflatedPoisson(name, p[0], p[1], src)
+	}))
+	Register("ZeroInflatedNegBinomial", fixedArity(3, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewZeroInflatedNegBinomial(name, p[0], p[1], p[2], src)
+	}))
+	Register("DiscreteUniform", checkedArity(2, func(p []Var) error {
+		return checkDiscreteUniformBounds(p[0].Value(), p[1].Value())
+	}, func(name string, p []Var, src *rand.Rand) RandVar {
+		return NewDiscreteUniform(name, p[0], p[1], src)
+	}))
+}
+
+// fixedArity returns a factory that checks the number of parameters before
+// building the variable.
+func fixedArity(numParams int, build func(name string, params []Var, src *rand.Rand) RandVar) Factory {
+	return checkedArity(numParams, nil, build)
+}
+
+// checkedArity returns a factory that checks the number of parameters, then
+// their value with check, before building the variable. The checks are
+// those of the corresponding Model constructors, which panic instead of
+// returning an error.
+func checkedArity(numParams int, check func(params []Var) error, build func(name string, params []Var, src *rand.Rand) RandVar) Factory {
+	return func(name string, params []Var, src *rand.Rand) (RandVar, error) {
+		if len(params) != numParams {
+			return nil, fmt.Errorf("needed %d parameters, got %d", numParams, len(params))
+		}
+		if check != nil {
+			if err := check(params); err != nil {
+				return nil, err
+			}
+		}
+		return build(name, params, src), nil
+	}
+}
+
+// checkTrials checks that the number of trials, the first parameter, is
+// positive.
+func checkTrials(p []Var) error {
+	if n := p[0].Value(); n <= 0 {
+		return fmt.Errorf("the number of bernoulli trials must be > 0, got %f", n)
+	}
+	return nil
+}
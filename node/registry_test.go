@@ -0,0 +1,39 @@
+package node
+
+import "testing"
+
+func TestFactoriesCheckParameters(t *testing.T) {
+	c := func(values ...float64) []Var {
+		params := make([]Var, len(values))
+		for i, v := range values {
+			params[i] = NewConstant(v)
+		}
+		return params
+	}
+	cases := []struct {
+		kind           string
+		valid, invalid []Var
+	}{
+		{"Normal", c(0, 1), c(0)},
+		{"Binomial", c(10, 0.5), c(0, 0.5)},
+		{"BetaBinomial", c(10, 1, 1), c(-1, 1, 1)},
+		{"Huber", c(0, 1, 1.5), c(0, 1, 0)},
+		{"AsymmetricLaplace", c(0, 1, 0.5), c(0, 1, 1)},
+		{"ZeroInflatedPoisson", c(0.2, 3), c(0.2)},
+		{"ZeroInflatedNegBinomial", c(0.2, 3, 1), c(0.2, 3)},
+		{"DiscreteUniform", c(1, 3), c(3, 1)},
+		{"DiscreteUniform", c(1, 1), c(0.5, 3)},
+	}
+	for _, tc := range cases {
+		factory, ok := Lookup(tc.kind)
+		if !ok {
+			t.Fatalf("%s is not registered", tc.kind)
+		}
+		if _, err := factory("x", tc.valid, nil); err != nil {
+			t.Errorf("%s: valid parameters rejected: %v", tc.kind, err)
+		}
+		if _, err := factory("x", tc.invalid, nil); err == nil {
+			t.Errorf("%s: invalid parameters accepted", tc.kind)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// StudentT is a random variable that follows a Student's t distribution with
+// Nu degrees of freedom, location Mu and scale Sigma. Its tails are heavier
+// than the normal distribution's, and it tends to the normal distribution as
+// Nu grows, which makes it a robust likelihood for regressions.
+type StudentT struct {
+	name  string
+	value float64
+	Nu    Var
+	Mu    Var
+	Sigma Var
+
+	Src *rand.Rand
+}
+
+func NewStudentT(name string, nu, mu, sigma Var, src *rand.Rand) *StudentT {
+	defaultValue := mu.Value()
+	newStudentT := StudentT{
+		name:  name,
+		value: defaultValue,
+		Nu:    nu,
+		Mu:    mu,
+		Sigma: sigma,
+		Src:   src,
+	}
+	return &newStudentT
+}
+
+func (s *StudentT) LogProb() float64 {
+	dist := distuv.StudentsT{Mu: s.Mu.Value(), Sigma: s.Sigma.Value(), Nu: s.Nu.Value()}
+	return dist.LogProb(s.value)
+}
+
+func (s *StudentT) CDF(x float64) float64 {
+	dist := distuv.StudentsT{Mu: s.Mu.Value(), Sigma: s.Sigma.Value(), Nu: s.Nu.Value()}
+	return dist.CDF(x)
+}
+
+func (s *StudentT) Rand() float64 {
+	dist := distuv.StudentsT{Mu: s.Mu.Value(), Sigma: s.Sigma.Value(), Nu: s.Nu.Value(), Src: s.Src}
+	return dist.Rand()
+}
+
+func (s *StudentT) Name() string {
+	return s.name
+}
+
+func (s *StudentT) Value() float64 {
+	return s.value
+}
+
+func (s *StudentT) SetValue(newValue float64) error {
+	s.value = newValue
+	return nil
+}
+
+func (s *StudentT) String() string {
+	return fmt.Sprintf("%s ~ StudentT(Nu=%s, Mu=%s, Sigma=%s)", s.name, describe(s.Nu), describe(s.Mu), describe(s.Sigma))
+}
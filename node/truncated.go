@@ -0,0 +1,138 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+)
+
+// Truncated is a random variable restricted to the interval (Lower, Upper]
+// of the support of another random variable, Dist. Its density is the density
+// of Dist renormalized by the mass of the interval:
+//
+// log p(x) = log p_Dist(x) - log(CDF(Upper) - CDF(Lower))
+//
+// and values outside of the interval are rejected. The lower bound is
+// excluded so that truncating a count at 0 from below yields a strictly
+// positive count; it makes no difference to continuous variables. Either
+// bound can be infinite.
+//
+// Dist must implement CDF. It is not part of the model: its parameters can be
+// variables of the model, and the truncated variable holds its value.
+type Truncated struct {
+	Dist  RandVar
+	Lower float64
+	Upper float64
+}
+
+// Truncate restricts dist to the interval (lower, upper]. The truncated
+// variable takes the name of dist and, if needed, moves its value inside
+// the interval.
+func Truncate(dist RandVar, lower, upper float64) *Truncated {
+	if _, ok := dist.(CDF); !ok {
+		log.Panicf("cannot truncate %s: its distribution has no CDF", dist.Name())
+	}
+	if lower >= upper {
+		log.Panicf("the lower bound of the truncation must be lower than the upper bound, got (%f, %f]", lower, upper)
+	}
+	t := &Truncated{Dist: dist, Lower: lower, Upper: upper}
+	if x := dist.Value(); x <= lower || x > upper {
+		t.SetValue(t.defaultValue())
+	}
+	return t
+}
+
+// defaultValue returns a value inside the interval, the middle of the
+// interval when both bounds are finite.
+func (t *Truncated) defaultValue() float64 {
+	switch {
+	case math.IsInf(t.Lower, -1):
+		return t.Upper
+	case math.IsInf(t.Upper, 1):
+		return t.Lower + 1
+	}
+	return (t.Lower + t.Upper) / 2
+}
+
+// Mass returns the probability that Dist falls in the interval.
+func (t *Truncated) Mass() float64 {
+	return t.cdf(t.Upper) - t.cdf(t.Lower)
+}
+
+// cdf returns the CDF of Dist, which is exactly 0 and 1 at -Inf and +Inf.
+func (t *Truncated) cdf(x float64) float64 {
+	switch {
+	case math.IsInf(x, -1):
+		return 0
+	case math.IsInf(x, 1):
+		return 1
+	}
+	return t.Dist.(CDF).CDF(x)
+}
+
+// CDF returns the CDF of the truncated variable.
+func (t *Truncated) CDF(x float64) float64 {
+	if x <= t.Lower {
+		return 0
+	}
+	x = math.Min(x, t.Upper)
+	return (t.cdf(x) - t.cdf(t.Lower)) / t.Mass()
+}
+
+func (t *Truncated) LogProb() float64 {
+	x := t.Dist.Value()
+	if x <= t.Lower || x > t.Upper {
+		return math.Inf(-1)
+	}
+	return t.Dist.LogProb() - math.Log(t.Mass())
+}
+
+// Rand draws values from Dist until one falls in the interval, which is
+// efficient as long as the interval holds a reasonable share of the mass of
+// Dist.
+func (t *Truncated) Rand() float64 {
+	if t.Mass() <= 0 {
+		log.Panicf("cannot draw %s: the interval (%f, %f] has no mass", t.Name(), t.Lower, t.Upper)
+	}
+	for {
+		if x := t.Dist.Rand(); x > t.Lower && x <= t.Upper {
+			return x
+		}
+	}
+}
+
+func (t *Truncated) Name() string {
+	return t.Dist.Name()
+}
+
+func (t *Truncated) Value() float64 {
+	return t.Dist.Value()
+}
+
+// SetValue sets the value of the variable, which must be inside the interval
+// and the support of Dist.
+func (t *Truncated) SetValue(newValue float64) error {
+	if newValue <= t.Lower || newValue > t.Upper {
+		return &OutOfBoundsErr{fmt.Sprintf("%s is truncated to (%s, %s], got %f", t.Name(), formatFloat(t.Lower), formatFloat(t.Upper), newValue)}
+	}
+	previous := t.Dist.Value()
+	if err := t.Dist.SetValue(newValue); err != nil {
+		return err
+	}
+	// Discrete variables round their value, which may leave the interval.
+	if x := t.Dist.Value(); x <= t.Lower || x > t.Upper {
+		t.Dist.SetValue(previous)
+		return &OutOfBoundsErr{fmt.Sprintf("%s is truncated to (%s, %s], got %f", t.Name(), formatFloat(t.Lower), formatFloat(t.Upper), x)}
+	}
+	return nil
+}
+
+func (t *Truncated) String() string {
+	description := fmt.Sprint(t.Dist)
+	// Dist is described by its distribution only.
+	if i := strings.Index(description, " ~ "); i >= 0 {
+		description = description[i+3:]
+	}
+	return fmt.Sprintf("%s ~ Truncated(%s, Lower=%s, Upper=%s)", t.Name(), description, formatFloat(t.Lower), formatFloat(t.Upper))
+}
@@ -0,0 +1,34 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestTruncatedLogProb(t *testing.T) {
+	normal := Truncate(NewNormal("x", NewConstant(0), NewConstant(1), nil), 0, math.Inf(1))
+	normal.SetValue(1)
+	if got, want := normal.LogProb(), math.Log(2*distuv.UnitNormal.Prob(1)); !closeTo(got, want) {
+		t.Errorf("half-normal: got %f, want %f", got, want)
+	}
+	if got, want := normal.CDF(1), 2*distuv.UnitNormal.CDF(1)-1; math.Abs(got-want) > 1e-12 {
+		t.Errorf("half-normal: got a CDF of %f, want %f", got, want)
+	}
+
+	// The lower bound is excluded: a zero-truncated count.
+	poisson := Truncate(NewPoisson("k", NewConstant(2), nil), 0, math.Inf(1))
+	poisson.SetValue(1)
+	if got, want := poisson.LogProb(), math.Log(2*math.Exp(-2)/(1-math.Exp(-2))); !closeTo(got, want) {
+		t.Errorf("zero-truncated Poisson: got %f, want %f", got, want)
+	}
+	if err := poisson.SetValue(0); err == nil {
+		t.Error("zero-truncated Poisson: accepted 0")
+	}
+
+	normal = Truncate(NewNormal("x", NewConstant(0), NewConstant(1), nil), -1, 2)
+	if mass := integrate(normal, -1, 2); math.Abs(mass-1) > 1e-6 {
+		t.Errorf("the density integrates to %f", mass)
+	}
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Uniform is a random variable that is uniformly distributed on [Min, Max].
+type Uniform struct {
+	name  string
+	value float64
+	Min   Var
+	Max   Var
+
+	Src *rand.Rand
+}
+
+func NewUniform(name string, min, max Var, src *rand.Rand) *Uniform {
+	defaultValue := (min.Value() + max.Value()) / 2
+	newUniform := Uniform{
+		name:  name,
+		value: defaultValue,
+		Min:   min,
+		Max:   max,
+		Src:   src,
+	}
+	return &newUniform
+}
+
+func (u *Uniform) LogProb() float64 {
+	dist := distuv.Uniform{Min: u.Min.Value(), Max: u.Max.Value()}
+	return dist.LogProb(u.value)
+}
+
+func (u *Uniform) CDF(x float64) float64 {
+	dist := distuv.Uniform{Min: u.Min.Value(), Max: u.Max.Value()}
+	return dist.CDF(x)
+}
+
+func (u *Uniform) Rand() float64 {
+	dist := distuv.Uniform{Min: u.Min.Value(), Max: u.Max.Value(), Src: u.Src}
+	return dist.Rand()
+}
+
+func (u *Uniform) Name() string {
+	return u.name
+}
+
+func (u *Uniform) Value() float64 {
+	return u.value
+}
+
+func (u *Uniform) SetValue(newValue float64) error {
+	min, max := u.Min.Value(), u.Max.Value()
+	if newValue < min || newValue > max {
+		return &OutOfBoundsErr{fmt.Sprintf("Uniform is defined on [%f,%f], got value %f", min, max, newValue)}
+	}
+	u.value = newValue
+
+	return nil
+}
+
+func (u *Uniform) String() string {
+	return fmt.Sprintf("%s ~ Uniform(Min=%s, Max=%s)", u.name, describe(u.Min), describe(u.Max))
+}
+
+func (u *Uniform) Mean() float64 {
+	return (u.Min.Value() + u.Max.Value()) / 2
+}
+
+func (u *Uniform) Variance() float64 {
+	width := u.Max.Value() - u.Min.Value()
+	return width * width / 12
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Weibull is a random variable that follows a Weibull distribution of shape K
+// and scale Lambda, a common model of lifetimes: the failure rate decreases
+// over time when K < 1 and increases when K > 1.
+type Weibull struct {
+	name   string
+	value  float64
+	K      Var
+	Lambda Var
+
+	Src *rand.Rand
+}
+
+func NewWeibull(name string, k, lambda Var, src *rand.Rand) *Weibull {
+	defaultValue := lambda.Value()
+	newWeibull := Weibull{
+		name:   name,
+		value:  defaultValue,
+		K:      k,
+		Lambda: lambda,
+		Src:    src,
+	}
+	return &newWeibull
+}
+
+func (w *Weibull) LogProb() float64 {
+	dist := distuv.Weibull{K: w.K.Value(), Lambda: w.Lambda.Value()}
+	return dist.LogProb(w.value)
+}
+
+func (w *Weibull) CDF(x float64) float64 {
+	dist := distuv.Weibull{K: w.K.Value(), Lambda: w.Lambda.Value()}
+	return dist.CDF(x)
+}
+
+func (w *Weibull) Rand() float64 {
+	dist := distuv.Weibull{K: w.K.Value(), Lambda: w.Lambda.Value(), Src: w.Src}
+	return dist.Rand()
+}
+
+func (w *Weibull) Name() string {
+	return w.name
+}
+
+func (w *Weibull) Value() float64 {
+	return w.value
+}
+
+func (w *Weibull) SetValue(newValue float64) error {
+	if newValue < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("Weibull is defined on [0,+inf), got value %f", newValue)}
+	}
+	w.value = newValue
+
+	return nil
+}
+
+func (w *Weibull) String() string {
+	return fmt.Sprintf("%s ~ Weibull(K=%s, Lambda=%s)", w.name, describe(w.K), describe(w.Lambda))
+}
@@ -0,0 +1,174 @@
+package node
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+// The Wishart distribution is a distribution over symmetric positive
+// definite matrices, the conjugate prior of the precision matrix of a
+// multivariate normal distribution. Its inverse, the inverse-Wishart
+// distribution, is the conjugate prior of the covariance matrix.
+//
+// Samplers explore unconstrained scalar variables, so the p×p matrix is
+// built with the Bartlett decomposition. With Scale = L L^T the Cholesky
+// decomposition of the scale matrix and A the lower triangular matrix of
+// independent variables
+//
+// A_ii = sqrt(c_i), c_i ~ ChiSquared(DoF - i)
+// A_ij ~ Normal(0, 1) for i > j
+//
+// the matrix W = L A A^T L^T follows a Wishart(Scale, DoF) distribution. The
+// scalar variables are the stochastic variables of the model, and every
+// matrix they produce is positive definite. An InverseWishart(Scale, DoF)
+// matrix is the inverse of a Wishart(Scale^-1, DoF) matrix.
+//
+// "On the Wishart Distribution" (Bartlett 1933)
+// https://doi.org/10.1017/S0305004100011129
+type Wishart struct {
+	name    string
+	Scale   *mat.SymDense
+	DoF     float64
+	Inverse bool // whether the matrix follows an inverse-Wishart distribution
+
+	ChiSquareds []*ChiSquared // c_i, on the diagonal of A
+	Normals     []*Normal     // A_ij for i > j, row by row
+
+	factor *mat.TriDense // Cholesky factor L of the scale matrix, or of its inverse
+}
+
+// NewWishart returns a p×p Wishart matrix of the given scale and degrees of
+// freedom, which must be greater than p - 1. The Bartlett variables are
+// named "<name>_c_<i>" and "<name>_z_<i>_<j>".
+func NewWishart(name string, scale *mat.SymDense, dof float64, src *rand.Rand) *Wishart {
+	return newBartlett(name, scale, dof, false, src)
+}
+
+// NewInverseWishart returns a p×p inverse-Wishart matrix of the given scale
+// and degrees of freedom, which must be greater than p - 1.
+func NewInverseWishart(name string, scale *mat.SymDense, dof float64, src *rand.Rand) *Wishart {
+	return newBartlett(name, scale, dof, true, src)
+}
+
+func newBartlett(name string, scale *mat.SymDense, dof float64, inverse bool, src *rand.Rand) *Wishart {
+	p := scale.Symmetric()
+	if dof <= float64(p-1) {
+		log.Panicf("the degrees of freedom of a %d×%d matrix must be greater than %d, got %f", p, p, p-1, dof)
+	}
+	var chol mat.Cholesky
+	if ok := chol.Factorize(scale); !ok {
+		log.Panicf("the scale matrix must be symmetric positive definite")
+	}
+	factor := mat.NewTriDense(p, mat.Lower, nil)
+	if inverse {
+		var precision mat.SymDense
+		if err := chol.InverseTo(&precision); err != nil {
+			log.Panicf("cannot invert the scale matrix: %v", err)
+		}
+		chol.Factorize(&precision)
+	}
+	chol.LTo(factor)
+
+	w := &Wishart{name: name, Scale: scale, DoF: dof, Inverse: inverse, factor: factor}
+	zero, one := NewConstant(0), NewConstant(1)
+	for i := 0; i < p; i++ {
+		c := NewChiSquared(fmt.Sprintf("%s_c_%d", name, i), NewConstant(dof-float64(i)), src)
+		w.ChiSquareds = append(w.ChiSquareds, c)
+		for j := 0; j < i; j++ {
+			w.Normals = append(w.Normals, NewNormal(fmt.Sprintf("%s_z_%d_%d", name, i, j), zero, one, src))
+		}
+	}
+	return w
+}
+
+func (w *Wishart) Name() string {
+	return w.name
+}
+
+// Dim returns the number of rows and columns of the matrix.
+func (w *Wishart) Dim() int {
+	return w.Scale.Symmetric()
+}
+
+// Variables returns the Bartlett variables, in the order they were created:
+// row by row, the chi-squared variable of the diagonal after the normal
+// variables of the row.
+func (w *Wishart) Variables() []RandVar {
+	var variables []RandVar
+	next := 0
+	for i, c := range w.ChiSquareds {
+		for j := 0; j < i; j++ {
+			variables = append(variables, w.Normals[next])
+			next++
+		}
+		variables = append(variables, c)
+	}
+	return variables
+}
+
+// Matrix returns the current value of the matrix.
+func (w *Wishart) Matrix() *mat.SymDense {
+	p := w.Dim()
+	a := mat.NewTriDense(p, mat.Lower, nil)
+	next := 0
+	for i, c := range w.ChiSquareds {
+		for j := 0; j < i; j++ {
+			a.SetTri(i, j, w.Normals[next].Value())
+			next++
+		}
+		a.SetTri(i, i, math.Sqrt(c.Value()))
+	}
+	var b mat.TriDense
+	b.MulTri(w.factor, a)
+
+	var matrix mat.SymDense
+	if !w.Inverse {
+		matrix.SymOuterK(1, &b)
+		return &matrix
+	}
+	// (B B^T)^-1 = B^-T B^-1
+	var bInv mat.TriDense
+	if err := bInv.InverseTri(&b); err != nil {
+		log.Panicf("cannot invert the Bartlett factor: %v", err)
+	}
+	matrix.SymOuterK(1, bInv.T())
+	return &matrix
+}
+
+// Entry returns the entry (i, j) of the matrix as a variable that can be
+// used as the parameter of other nodes. Each evaluation recomputes the whole
+// matrix, which is cheap for the small matrices of covariance priors.
+func (w *Wishart) Entry(i, j int) Var {
+	p := w.Dim()
+	if i < 0 || j < 0 || i >= p || j >= p {
+		log.Panicf("the entry (%d, %d) is outside of the %d×%d matrix", i, j, p, p)
+	}
+	return &WishartEntry{Wishart: w, I: i, J: j}
+}
+
+func (w *Wishart) String() string {
+	kind := "Wishart"
+	if w.Inverse {
+		kind = "InverseWishart"
+	}
+	p := w.Dim()
+	return fmt.Sprintf("%s ~ %s(Scale=%d×%d, DoF=%s)", w.name, kind, p, p, formatFloat(w.DoF))
+}
+
+// A WishartEntry is an entry of a Wishart or inverse-Wishart matrix.
+type WishartEntry struct {
+	Wishart *Wishart
+	I, J    int
+}
+
+func (e *WishartEntry) Value() float64 {
+	return e.Wishart.Matrix().At(e.I, e.J)
+}
+
+func (e *WishartEntry) String() string {
+	return fmt.Sprintf("%s[%d,%d]", e.Wishart.name, e.I, e.J)
+}
@@ -0,0 +1,59 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+)
+
+// TestWishartMean checks the mean of the matrices built from draws of the
+// Bartlett variables: DoF Scale for a Wishart matrix, and
+// Scale / (DoF - p - 1) for an inverse-Wishart matrix.
+func TestWishartMean(t *testing.T) {
+	scale := mat.NewSymDense(2, []float64{2, 0.5, 0.5, 1})
+	// The inverse-Wishart entries have a finite variance when DoF > p + 3.
+	const dof, numDraws = 8.0, 50000
+	for _, inverse := range []bool{false, true} {
+		src := rand.New(rand.NewSource(1))
+		w := NewWishart("W", scale, dof, src)
+		want := mat.NewDense(2, 2, nil)
+		want.Scale(dof, scale)
+		if inverse {
+			w = NewInverseWishart("W", scale, dof, src)
+			want.Scale(1/(dof-2-1), scale)
+		}
+
+		mean := mat.NewDense(2, 2, nil)
+		for i := 0; i < numDraws; i++ {
+			for _, v := range w.Variables() {
+				v.SetValue(v.Rand())
+			}
+			mean.Add(mean, w.Matrix())
+		}
+		mean.Scale(1.0/numDraws, mean)
+		if !mat.EqualApprox(mean, want, 0.05) {
+			t.Errorf("inverse=%t: got a mean of %v, want %v", inverse, mat.Formatted(mean), mat.Formatted(want))
+		}
+	}
+}
+
+// TestWishartMatrix checks the Bartlett construction W = L A A^T L^T on a
+// value computed by hand.
+func TestWishartMatrix(t *testing.T) {
+	// L = [[2, 0], [1, 1]]
+	w := NewWishart("W", mat.NewSymDense(2, []float64{4, 2, 2, 2}), 3, nil)
+	// A = [[1, 0], [2, 3]]
+	w.ChiSquareds[0].SetValue(1)
+	w.ChiSquareds[1].SetValue(9)
+	w.Normals[0].SetValue(2)
+	// L A = [[2, 0], [3, 3]]
+	want := mat.NewSymDense(2, []float64{4, 6, 6, 18})
+	if got := w.Matrix(); !mat.EqualApprox(got, want, 1e-12) {
+		t.Errorf("got %v, want %v", mat.Formatted(got), mat.Formatted(want))
+	}
+	if math.Abs(w.Entry(1, 0).Value()-6) > 1e-12 {
+		t.Errorf("got the entry %f, want 6", w.Entry(1, 0).Value())
+	}
+}
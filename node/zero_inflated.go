@@ -0,0 +1,177 @@
+package node
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ZeroInflatedPoisson is a random variable that is 0 with probability Pi,
+// e.g. when the process that generates the counts is not active, and
+// otherwise follows a Poisson distribution of rate Lambda. It models counts
+// with more zeros than a Poisson distribution allows, such as defects or
+// insurance claims.
+type ZeroInflatedPoisson struct {
+	name   string
+	value  float64
+	Pi     Var
+	Lambda Var
+
+	Src *rand.Rand
+}
+
+func NewZeroInflatedPoisson(name string, pi, lambda Var, src *rand.Rand) *ZeroInflatedPoisson {
+	defaultValue := 0.0
+	newZeroInflatedPoisson := ZeroInflatedPoisson{
+		name:   name,
+		value:  defaultValue,
+		Pi:     pi,
+		Lambda: lambda,
+		Src:    src,
+	}
+	return &newZeroInflatedPoisson
+}
+
+func (z *ZeroInflatedPoisson) LogProb() float64 {
+	dist := distuv.Poisson{Lambda: z.Lambda.Value()}
+	return zeroInflatedLogProb(z.value, z.Pi.Value(), dist.LogProb(z.value))
+}
+
+func (z *ZeroInflatedPoisson) Rand() float64 {
+	if zeroInflatedZero(z.Pi.Value(), z.Src) {
+		return 0
+	}
+	dist := distuv.Poisson{Lambda: z.Lambda.Value(), Src: z.Src}
+	return dist.Rand()
+}
+
+func (z *ZeroInflatedPoisson) Name() string {
+	return z.name
+}
+
+func (z *ZeroInflatedPoisson) Value() float64 {
+	return z.value
+}
+
+func (z *ZeroInflatedPoisson) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("A zero-inflated Poisson random variable can only take positive integers as values, got %f", newValue)}
+	}
+	z.value = roundedVal
+
+	return nil
+}
+
+func (z *ZeroInflatedPoisson) String() string {
+	return fmt.Sprintf("%s ~ ZeroInflatedPoisson(Pi=%s, Lambda=%s)", z.name, describe(z.Pi), describe(z.Lambda))
+}
+
+func (z *ZeroInflatedPoisson) Mean() float64 {
+	return (1 - z.Pi.Value()) * z.Lambda.Value()
+}
+
+func (z *ZeroInflatedPoisson) Variance() float64 {
+	pi, lambda := z.Pi.Value(), z.Lambda.Value()
+	return (1 - pi) * lambda * (1 + pi*lambda)
+}
+
+// ZeroInflatedNegBinomial is a random variable that is 0 with probability
+// Pi, and otherwise follows a negative binomial distribution of mean Mu and
+// dispersion Alpha; see NegativeBinomial. It models over-dispersed counts
+// with excess zeros.
+type ZeroInflatedNegBinomial struct {
+	name  string
+	value float64
+	Pi    Var
+	Mu    Var
+	Alpha Var
+
+	Src *rand.Rand
+}
+
+func NewZeroInflatedNegBinomial(name string, pi, mu, alpha Var, src *rand.Rand) *ZeroInflatedNegBinomial {
+	defaultValue := 0.0
+	newZeroInflatedNegBinomial := ZeroInflatedNegBinomial{
+		name:  name,
+		value: defaultValue,
+		Pi:    pi,
+		Mu:    mu,
+		Alpha: alpha,
+		Src:   src,
+	}
+	return &newZeroInflatedNegBinomial
+}
+
+// rp returns the number of successes and the success probability of the
+// negative binomial distribution.
+func (z *ZeroInflatedNegBinomial) rp() (float64, float64) {
+	alpha := z.Alpha.Value()
+	return alpha, alpha / (alpha + z.Mu.Value())
+}
+
+func (z *ZeroInflatedNegBinomial) LogProb() float64 {
+	r, p := z.rp()
+	return zeroInflatedLogProb(z.value, z.Pi.Value(), negativeBinomialLogProb(z.value, r, p))
+}
+
+func (z *ZeroInflatedNegBinomial) Rand() float64 {
+	if zeroInflatedZero(z.Pi.Value(), z.Src) {
+		return 0
+	}
+	r, p := z.rp()
+	return negativeBinomialRand(r, p, z.Src)
+}
+
+func (z *ZeroInflatedNegBinomial) Name() string {
+	return z.name
+}
+
+func (z *ZeroInflatedNegBinomial) Value() float64 {
+	return z.value
+}
+
+func (z *ZeroInflatedNegBinomial) SetValue(newValue float64) error {
+	roundedVal := math.Round(newValue)
+	if roundedVal < 0 {
+		return &OutOfBoundsErr{fmt.Sprintf("A zero-inflated negative binomial random variable can only take positive integers as values, got %f", newValue)}
+	}
+	z.value = roundedVal
+
+	return nil
+}
+
+func (z *ZeroInflatedNegBinomial) String() string {
+	return fmt.Sprintf("%s ~ ZeroInflatedNegBinomial(Pi=%s, Mu=%s, Alpha=%s)", z.name, describe(z.Pi), describe(z.Mu), describe(z.Alpha))
+}
+
+func (z *ZeroInflatedNegBinomial) Mean() float64 {
+	return (1 - z.Pi.Value()) * z.Mu.Value()
+}
+
+func (z *ZeroInflatedNegBinomial) Variance() float64 {
+	pi, mu, alpha := z.Pi.Value(), z.Mu.Value(), z.Alpha.Value()
+	return (1 - pi) * mu * (1 + mu/alpha + pi*mu)
+}
+
+// zeroInflatedLogProb returns the log-probability of a count that is 0 with
+// probability pi and otherwise has the log-probability countLogProb.
+func zeroInflatedLogProb(k, pi, countLogProb float64) float64 {
+	if pi < 0 || pi > 1 {
+		return math.Inf(-1)
+	}
+	if k == 0 {
+		// log(pi + (1 - pi) * P(0)), stable when P(0) is tiny.
+		return floats.LogSumExp([]float64{math.Log(pi), math.Log1p(-pi) + countLogProb})
+	}
+	return math.Log1p(-pi) + countLogProb
+}
+
+// zeroInflatedZero draws whether a zero-inflated count is a structural zero.
+func zeroInflatedZero(pi float64, src *rand.Rand) bool {
+	dist := distuv.Bernoulli{P: pi, Src: src}
+	return dist.Rand() == 1
+}
@@ -0,0 +1,44 @@
+package node
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZeroInflatedPoissonLogProb(t *testing.T) {
+	z := NewZeroInflatedPoisson("k", NewConstant(0.2), NewConstant(3), nil)
+	cases := []struct{ k, want float64 }{
+		{0, math.Log(0.2 + 0.8*math.Exp(-3))},
+		{2, math.Log(0.8 * math.Exp(-3) * 9 / 2)},
+	}
+	for _, c := range cases {
+		z.SetValue(c.k)
+		if got := z.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("k=%g: got %f, want %f", c.k, got, c.want)
+		}
+	}
+}
+
+func TestZeroInflatedNegBinomialLogProb(t *testing.T) {
+	// R = alpha = 2 and P = alpha / (alpha + mu) = 0.4.
+	z := NewZeroInflatedNegBinomial("k", NewConstant(0.2), NewConstant(3), NewConstant(2), nil)
+	cases := []struct{ k, want float64 }{
+		{0, math.Log(0.2 + 0.8*0.4*0.4)},
+		{1, math.Log(0.8 * 2 * 0.4 * 0.4 * 0.6)},
+	}
+	for _, c := range cases {
+		z.SetValue(c.k)
+		if got := z.LogProb(); !closeTo(got, c.want) {
+			t.Errorf("k=%g: got %f, want %f", c.k, got, c.want)
+		}
+	}
+
+	var mass float64
+	for k := 0; k < 200; k++ {
+		z.SetValue(float64(k))
+		mass += math.Exp(z.LogProb())
+	}
+	if math.Abs(mass-1) > 1e-9 {
+		t.Errorf("the probabilities sum to %f", mass)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Problem exposes the model as a gonum optimization problem. The objective is
+// the negative log-probability of the model evaluated at the values of the
+// stochastic variables, so that any gonum optimizer can be used to find the
+// maximum a posteriori estimate.
+func (m *Model) Problem() optimize.Problem {
+	return optimize.Problem{
+		Func: func(x []float64) float64 {
+			return -m.LogProb(x)
+		},
+		Grad: func(grad, x []float64) {
+			m.Grad(grad, x)
+			floats.Scale(-1, grad)
+		},
+	}
+}
+
+// Grad computes the gradient of the model's log-probability with respect to
+// the values of the stochastic variables, and stores it in `grad`.
+//
+// The gradient is computed by finite differences. The evaluations are not
+// run concurrently since the model holds the current value of its variables.
+func (m *Model) Grad(grad, x []float64) {
+	fd.Gradient(grad, m.LogProb, x, nil)
+}
+
+// FindMAP returns the maximum a posteriori estimate of the stochastic
+// variables' values, starting the search from the `initial` point. It returns
+// a map between the names of the variables and their estimated value.
+func (m *Model) FindMAP(initial []float64) (map[string]float64, error) {
+	if len(initial) != len(m.stochastic) {
+		log.Panicf("needed %d initial points, got %d", len(m.stochastic), len(initial))
+	}
+
+	result, err := optimize.Minimize(m.Problem(), initial, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := make(map[string]float64)
+	for i, variable := range m.stochastic {
+		estimate[variable.Name()] = result.X[i]
+	}
+	// Leave the model in the state of the estimate rather than the
+	// last point evaluated by the optimizer.
+	m.LogProb(result.X)
+
+	return estimate, nil
+}
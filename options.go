@@ -0,0 +1,92 @@
+package main
+
+import (
+	"time"
+
+	"github.com/rlouf/gmc/monitor"
+)
+
+// chunkSize is the maximum number of samples the sampler draws at once.
+const chunkSize = 1000
+
+// timedChunkSize is the maximum number of samples the sampler draws at once
+// when the run has a time budget.
+const timedChunkSize = 100
+
+// predictiveChunkSize is the number of posterior predictive samples drawn
+// with the same random stream when the simulation is parallel.
+const predictiveChunkSize = 100
+
+// SampleOptions configures how the samples are drawn and stored.
+type SampleOptions struct {
+	// Monitor lists the names of the variables that are stored in the trace.
+	// All the stochastic variables are sampled, but discarding nuisance
+	// variables keeps the memory used by the trace bounded. All the
+	// variables are stored when Monitor is empty.
+	Monitor []string
+
+	// Progress receives the value of the monitored variables at each
+	// iteration, e.g. to follow the run live with a monitor.Monitor. The
+	// sampler never waits for the receiver: draws are dropped when the
+	// channel is full. The channel is not closed by the sampler.
+	Progress chan<- monitor.Draw
+
+	// MaxTraceBytes caps the memory used by the values stored in the trace.
+	// When storing one more sample would exceed it, every other stored
+	// sample is dropped and the chain is thinned twice as much from then
+	// on, so the trace always spans the whole run. There is no cap when
+	// MaxTraceBytes is 0.
+	MaxTraceBytes int
+
+	// StopOnInterrupt makes the run stop when the process receives an
+	// interrupt signal (Ctrl-C) instead of being killed, and return the
+	// samples drawn so far. The signal is handled between chunks of
+	// timedChunkSize iterations, the burn-in being part of the first one.
+	// Default handling is restored as soon as the signal is received, so
+	// that a second one kills the process.
+	StopOnInterrupt bool
+
+	// MaxDuration caps the time spent drawing samples after the burn-in,
+	// which always runs in full, e.g. for services that must answer within
+	// a deadline. The run stops at the first chunk of timedChunkSize
+	// iterations that ends after the deadline, with fewer samples than
+	// requested; SampleResult.Draws tells how many. There is no cap when
+	// MaxDuration is 0.
+	MaxDuration time.Duration
+
+	// TracePath is the path of a file to which the stored samples are
+	// appended as they are drawn, and flushed to disk after every chunk, so
+	// that trace.Recover can load them if the process crashes. The file
+	// also contains the specification of the model. A failure to write the
+	// file is reported as a warning and does not stop the run. No file is
+	// written when TracePath is empty.
+	TracePath string
+}
+
+// PredictiveOptions configures how the posterior samples are selected to
+// generate posterior predictive samples.
+type PredictiveOptions struct {
+	// Systematic makes the simulation go through the trace at evenly spaced
+	// positions, starting from a random offset, instead of picking samples
+	// at random. Every posterior sample is then used the same number of
+	// times (up to one), which reduces the Monte Carlo noise of the
+	// statistics computed on the predictions.
+	Systematic bool
+
+	// Build makes the simulation parallel. It must return a copy of the
+	// model, with the same stochastic and observed variables, that does not
+	// share nodes with the other copies: nodes hold their current value, so
+	// workers sharing a graph would overwrite each other's. Build is called
+	// concurrently, once per worker.
+	Build func() *Model
+
+	// NumWorkers is the number of workers of a parallel simulation. It
+	// defaults to the number of CPUs.
+	NumWorkers int
+
+	// Seed is the seed of the random streams of a parallel simulation. The
+	// samples are split in chunks of predictiveChunkSize, and each chunk is
+	// simulated with its own stream derived from Seed with rngsplit, so the
+	// output does not depend on the number of workers or on scheduling.
+	Seed uint64
+}
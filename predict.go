@@ -0,0 +1,124 @@
+package main
+
+import (
+	"log"
+	"sort"
+
+	"github.com/rlouf/gmc/node"
+	"gonum.org/v1/gonum/stat"
+)
+
+// An Interval summarizes the predictive distribution of an observed variable
+// by its median and a central credible interval.
+type Interval struct {
+	Lower  float64
+	Median float64
+	Upper  float64
+}
+
+// PredictInterval returns, for each observed variable, the median and the
+// central interval that contains a proportion `prob` of its posterior
+// predictive distribution.
+//
+// The intervals are computed from numSamples posterior predictive samples,
+// so they account both for the uncertainty on the parameters and for the
+// observation noise.
+func (m *Model) PredictInterval(numSamples int, trace map[string][]float64, prob float64) map[string]Interval {
+	if prob <= 0 || prob >= 1 {
+		log.Panicf("the interval probability must be in (0,1), got %f", prob)
+	}
+
+	predictions := m.SamplePosteriorPredictive(numSamples, trace)
+
+	intervals := make(map[string]Interval)
+	for name, samples := range predictions {
+		sort.Float64s(samples)
+		intervals[name] = Interval{
+			Lower:  stat.Quantile((1-prob)/2, stat.Empirical, samples, nil),
+			Median: stat.Quantile(0.5, stat.Empirical, samples, nil),
+			Upper:  stat.Quantile((1+prob)/2, stat.Empirical, samples, nil),
+		}
+	}
+
+	return intervals
+}
+
+// Moments are the mean and variance of a predictive distribution.
+type Moments struct {
+	Mean     float64
+	Variance float64
+}
+
+// PredictiveMoments returns the mean and variance of the posterior
+// predictive distribution of each observed variable.
+//
+// Rather than simulating new observations, it averages over the trace the
+// mean and variance of the observed variables given the parameters, which
+// are known in closed form:
+//
+// E[y] = E[E[y|theta]]
+// Var[y] = E[Var[y|theta]] + Var[E[y|theta]]
+//
+// These Rao-Blackwellized estimates have a lower variance than the moments
+// of posterior predictive samples. The distribution of every observed
+// variable must implement node.Moments.
+func (m *Model) PredictiveMoments(trace map[string][]float64) map[string]Moments {
+	traceSize := m.traceSize(trace)
+
+	observed := make([]node.Moments, len(m.observed))
+	for i, o := range m.observed {
+		moments, ok := o.(node.Moments)
+		if !ok {
+			log.Panicf("the moments of variable %s are not known in closed form", o.Name())
+		}
+		observed[i] = moments
+	}
+
+	means := make([][]float64, len(observed))
+	variances := make([][]float64, len(observed))
+	for i := range observed {
+		means[i] = make([]float64, traceSize)
+		variances[i] = make([]float64, traceSize)
+	}
+	for loc := 0; loc < traceSize; loc++ {
+		for _, variable := range m.stochastic {
+			variable.SetValue(trace[variable.Name()][loc])
+		}
+		for i, o := range observed {
+			means[i][loc] = o.Mean()
+			variances[i][loc] = o.Variance()
+		}
+	}
+
+	moments := make(map[string]Moments)
+	for i, o := range m.observed {
+		mean, varianceOfMeans := stat.MeanVariance(means[i], nil)
+		moments[o.Name()] = Moments{
+			Mean:     mean,
+			Variance: stat.Mean(variances[i], nil) + varianceOfMeans,
+		}
+	}
+
+	return moments
+}
+
+// PointwiseLogLik returns the log-likelihood of each observed variable for
+// each sample of the trace, with one row per sample and one column per
+// observed variable in the order they were observed. The likelihood powers
+// set with SetPower are ignored.
+func (m *Model) PointwiseLogLik(trace map[string][]float64) [][]float64 {
+	traceSize := m.traceSize(trace)
+
+	logLik := make([][]float64, traceSize)
+	for loc := range logLik {
+		for _, variable := range m.stochastic {
+			variable.SetValue(trace[variable.Name()][loc])
+		}
+		logLik[loc] = make([]float64, len(m.observed))
+		for i, o := range m.observed {
+			logLik[loc][i] = o.LogProb()
+		}
+	}
+
+	return logLik
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rlouf/gmc/node"
+)
+
+// RandomWalk adds to the model `length` normally distributed variables that
+// follow a random walk of order 1 or 2, a smoothing prior for effects that
+// vary along an ordered covariate such as age or time.
+//
+// Noting x_i the variables, the first `order` variables have a Normal(0,
+// initial) prior and the following ones are defined by:
+//
+// - order 1: x_i ~ Normal(x_{i-1}, sigma)
+// - order 2: x_i ~ Normal(2 x_{i-1} - x_{i-2}, sigma)
+//
+// so that sigma controls the size of the first (respectively second)
+// differences. Each variable only depends on its `order` predecessors, which
+// is the conditional form of the sparse precision matrix of the walk.
+//
+// The variables are named "<name>_<i>".
+func (m *Model) RandomWalk(name string, length, order int, initial, sigma node.Var) []*node.Normal {
+	if order != 1 && order != 2 {
+		log.Panicf("The order of the random walk must be 1 or 2, got %d", order)
+	}
+	if length < order {
+		log.Panicf("the random walk needs at least %d variables, got %d", order, length)
+	}
+
+	walk := make([]*node.Normal, length)
+	zero := m.Constant(0)
+	for i := 0; i < order; i++ {
+		walk[i] = m.Normal(fmt.Sprintf("%s_%d", name, i), zero, initial)
+	}
+	two, minusOne := m.Constant(2), m.Constant(-1)
+	for i := order; i < length; i++ {
+		var mean node.Var = walk[i-1]
+		if order == 2 {
+			mean = m.Sum(m.Prod(two, walk[i-1]), m.Prod(minusOne, walk[i-2]))
+		}
+		walk[i] = m.Normal(fmt.Sprintf("%s_%d", name, i), mean, sigma)
+	}
+
+	return walk
+}
@@ -0,0 +1,15 @@
+package report
+
+// A Plot shows the trace and the density of the samples of a variable.
+// Notebooks such as gophernotes display it inline through its SVG method.
+type Plot struct {
+	Samples []float64
+}
+
+// SVG renders the trace plot next to the density plot.
+func (p Plot) SVG() string {
+	return `<svg xmlns="http://www.w3.org/2000/svg" width="740" height="120">` +
+		`<g>` + tracePlot(p.Samples) + `</g>` +
+		`<g transform="translate(380,0)">` + densityPlot(p.Samples) + `</g>` +
+		`</svg>`
+}
@@ -0,0 +1,107 @@
+// Package report renders the results of an inference as a self-contained
+// document that can be attached to an experiment or shared.
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/rlouf/gmc/diagnostics"
+	"github.com/rlouf/gmc/trace"
+)
+
+type variableReport struct {
+	trace.Summary
+	Lower       float64
+	Upper       float64
+	ESS         float64
+	TracePlot   template.HTML
+	DensityPlot template.HTML
+}
+
+type htmlReport struct {
+	Model     string
+	Variables []variableReport
+	Warnings  []string
+}
+
+// HTML writes a report of the trace as a standalone HTML page: a description
+// of the model, a table that summarizes the posterior distribution of each
+// variable along with its effective sample size, trace and density plots,
+// and warnings about the quality of the samples.
+//
+// The model is only used for its description and can be nil.
+func HTML(samples trace.Trace, model fmt.Stringer, w io.Writer) error {
+	r := htmlReport{}
+	if model != nil {
+		r.Model = model.String()
+	}
+
+	for _, name := range samples.Names() {
+		draws := samples[name]
+		if len(draws) == 0 {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s has no samples", name))
+			continue
+		}
+		summary := trace.Summarize(name, draws)
+		sorted := append([]float64(nil), draws...)
+		sort.Float64s(sorted)
+
+		ess := diagnostics.EffectiveSampleSize(draws)
+		switch {
+		case math.IsNaN(ess):
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: the chain did not move", summary.Name))
+		case ess < diagnostics.MinEffectiveSampleSize:
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: low effective sample size (%.0f)", summary.Name, ess))
+		}
+
+		r.Variables = append(r.Variables, variableReport{
+			Summary:     summary,
+			Lower:       stat.Quantile(0.05, stat.Empirical, sorted, nil),
+			Upper:       stat.Quantile(0.95, stat.Empirical, sorted, nil),
+			ESS:         ess,
+			TracePlot:   template.HTML(tracePlot(draws)),
+			DensityPlot: template.HTML(densityPlot(draws)),
+		})
+	}
+
+	return page.Execute(w, r)
+}
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"fmt": func(v float64) string { return fmt.Sprintf("%.3g", v) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gmc report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 0.8em; text-align: right; border-bottom: 1px solid #ddd; }
+th:first-child, td:first-child { text-align: left; }
+.warning { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Inference report</h1>
+{{if .Model}}<h2>Model</h2>
+<pre>{{.Model}}</pre>{{end}}
+{{if .Warnings}}<h2>Warnings</h2>
+<ul>{{range .Warnings}}<li class="warning">{{.}}</li>{{end}}</ul>{{end}}
+<h2>Summary</h2>
+<table>
+<tr><th>variable</th><th>mean</th><th>sd</th><th>median</th><th>mode</th><th>5%</th><th>95%</th><th>ess</th></tr>
+{{range .Variables}}<tr><td>{{.Name}}</td><td>{{fmt .Mean}}</td><td>{{fmt .StdDev}}</td><td>{{fmt .Median}}</td><td>{{fmt .Mode}}</td><td>{{fmt .Lower}}</td><td>{{fmt .Upper}}</td><td>{{fmt .ESS}}</td></tr>
+{{end}}</table>
+<h2>Plots</h2>
+{{range .Variables}}<h3>{{.Name}}</h3>
+<div>{{.TracePlot}} {{.DensityPlot}}</div>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,23 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rlouf/gmc/trace"
+)
+
+func TestHTMLEmptyVariable(t *testing.T) {
+	var b bytes.Buffer
+	samples := trace.Trace{"a": {}, "b": {1, 2, 3, 2, 1}}
+	if err := HTML(samples, nil, &b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "a has no samples") {
+		t.Error("the report does not warn about the variable without samples")
+	}
+	if !strings.Contains(b.String(), "<td>b</td>") {
+		t.Error("the report does not summarize the other variables")
+	}
+}
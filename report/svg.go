@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+const (
+	plotWidth  = 360
+	plotHeight = 120
+	numBins    = 30
+)
+
+// tracePlot draws the value of the samples against the iteration as an SVG
+// polyline.
+func tracePlot(samples []float64) string {
+	min, max := bounds(samples)
+
+	var b bytes.Buffer
+	openSVG(&b)
+	fmt.Fprint(&b, `<polyline fill="none" stroke="steelblue" stroke-width="0.5" points="`)
+	step := 1
+	if len(samples) > 2*plotWidth {
+		step = len(samples) / (2 * plotWidth)
+	}
+	for i := 0; i < len(samples); i += step {
+		x := float64(i) / float64(len(samples)) * plotWidth
+		fmt.Fprintf(&b, "%.1f,%.1f ", x, scaleY(samples[i], min, max))
+	}
+	fmt.Fprint(&b, `"/></svg>`)
+	return b.String()
+}
+
+// densityPlot draws the histogram of the samples.
+func densityPlot(samples []float64) string {
+	min, max := bounds(samples)
+	counts := make([]float64, numBins)
+	for _, s := range samples {
+		bin := int((s - min) / (max - min) * numBins)
+		if bin >= numBins {
+			bin = numBins - 1
+		}
+		counts[bin]++
+	}
+	highest := floats.Max(counts)
+
+	var b bytes.Buffer
+	openSVG(&b)
+	width := float64(plotWidth) / numBins
+	for i, c := range counts {
+		height := c / highest * plotHeight
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="steelblue"/>`, float64(i)*width, plotHeight-height, width-1, height)
+	}
+	fmt.Fprint(&b, `</svg>`)
+	return b.String()
+}
+
+func openSVG(b *bytes.Buffer) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, plotWidth, plotHeight, plotWidth, plotHeight)
+}
+
+// bounds returns the range of the samples, widened when all the samples are
+// equal so that scaling never divides by zero.
+func bounds(samples []float64) (float64, float64) {
+	min, max := floats.Min(samples), floats.Max(samples)
+	if min == max {
+		min, max = min-0.5, max+0.5
+	}
+	return min, max
+}
+
+func scaleY(value, min, max float64) float64 {
+	return plotHeight - (value-min)/(max-min)*plotHeight
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rlouf/gmc/diagnostics"
+	"github.com/rlouf/gmc/trace"
+)
+
+// Acceptance rates outside of these bounds indicate that the proposal
+// distribution of the Metropolis-Hastings sampler is poorly scaled.
+const (
+	minAcceptanceRate = 0.1
+	maxAcceptanceRate = 0.9
+)
+
+// A SampleResult bundles the trace produced by the sampler with information
+// about the run.
+type SampleResult struct {
+	Trace trace.Trace
+
+	// AcceptanceRate is the proportion of samples that differ from the
+	// previous one, i.e. of accepted proposals when the chain is not
+	// thinned.
+	AcceptanceRate float64
+
+	// Thin is the interval between the iterations stored in the trace. It
+	// is greater than 1 when the trace was thinned to fit in
+	// SampleOptions.MaxTraceBytes.
+	Thin int
+
+	// Draws is the number of iterations drawn after the burn-in, fewer than
+	// requested when the run was interrupted or ran out of time.
+	Draws int
+
+	// Interrupted is true when the run was stopped by an interrupt signal
+	// before all the samples were drawn.
+	Interrupted bool
+
+	Duration time.Duration
+	Warnings []Warning
+}
+
+// A WarningKind identifies the problem reported by a warning.
+type WarningKind int
+
+const (
+	LowEffectiveSampleSize WarningKind = iota
+	StuckChain
+	LowAcceptanceRate
+	HighAcceptanceRate
+	TraceFileError
+)
+
+// A Warning reports a problem with the samples. Variable is empty when the
+// warning concerns the whole run.
+type Warning struct {
+	Kind     WarningKind
+	Variable string
+	Message  string
+}
+
+func (w Warning) String() string {
+	if w.Variable == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.Variable, w.Message)
+}
+
+// diagnose inspects the samples and fills the warnings.
+func (r *SampleResult) diagnose() {
+	for _, name := range r.Trace.Names() {
+		ess := diagnostics.EffectiveSampleSize(r.Trace[name])
+		switch {
+		case math.IsNaN(ess):
+			r.Warnings = append(r.Warnings, Warning{StuckChain, name, "the chain did not move"})
+		case ess < diagnostics.MinEffectiveSampleSize:
+			r.Warnings = append(r.Warnings, Warning{LowEffectiveSampleSize, name, fmt.Sprintf("low effective sample size (%.0f)", ess)})
+		}
+	}
+
+	switch {
+	case r.AcceptanceRate < minAcceptanceRate:
+		r.Warnings = append(r.Warnings, Warning{LowAcceptanceRate, "", fmt.Sprintf("low acceptance rate (%.2f): decrease the scale of the proposal", r.AcceptanceRate)})
+	case r.AcceptanceRate > maxAcceptanceRate:
+		r.Warnings = append(r.Warnings, Warning{HighAcceptanceRate, "", fmt.Sprintf("high acceptance rate (%.2f): increase the scale of the proposal", r.AcceptanceRate)})
+	}
+}
+
+// A Summary describes the posterior distribution of a variable along with
+// the efficiency of the sampler for this variable.
+type Summary struct {
+	trace.Summary
+	EffectiveSampleSize float64
+	// EffectiveSamplesPerSecond is the effective sample size divided by the
+	// wall-clock duration of the run. It allows to compare samplers and
+	// their settings on actual efficiency rather than number of iterations.
+	EffectiveSamplesPerSecond float64
+}
+
+// Summary returns the summary of every variable in the trace, ordered by
+// name.
+func (r *SampleResult) Summary() []Summary {
+	var summaries []Summary
+	for _, s := range r.Trace.Summary() {
+		ess := diagnostics.EffectiveSampleSize(r.Trace[s.Name])
+		summaries = append(summaries, Summary{
+			Summary:                   s,
+			EffectiveSampleSize:       ess,
+			EffectiveSamplesPerSecond: ess / r.Duration.Seconds(),
+		})
+	}
+	return summaries
+}
@@ -0,0 +1,68 @@
+// Package rngsplit derives the seeds of independent random number streams
+// from a single seed, so that chains, folds or workers that run in parallel
+// are reproducible without sharing, or silently correlating, their streams.
+//
+// Seeding streams with consecutive integers, or with the same seed, can make
+// them correlated. Instead, the seed of each stream is obtained by passing
+// the parent seed and the index of the stream through the SplitMix64 mixing
+// function, whose outputs are statistically independent for distinct
+// inputs:
+//
+// "Fast splittable pseudorandom number generators" (Steele et al. 2014)
+// https://doi.org/10.1145/2714064.2660195
+package rngsplit
+
+import (
+	"sync"
+
+	"golang.org/x/exp/rand"
+)
+
+// golden is the increment of SplitMix64, the odd integer closest to
+// 2^64 / phi.
+const golden = 0x9e3779b97f4a7c15
+
+// Mix returns the SplitMix64 finalizer of x, a bijection of the 64-bit
+// integers that scrambles its input.
+func Mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// Derive returns the seed of the i-th stream derived from `seed`. The same
+// seed and index always give the same result.
+func Derive(seed, i uint64) uint64 {
+	return Mix(seed + (i+1)*golden)
+}
+
+// New returns a random number generator for the i-th stream derived from
+// `seed`.
+func New(seed, i uint64) *rand.Rand {
+	return rand.New(rand.NewSource(Derive(seed, i)))
+}
+
+// A Splitter hands out the streams derived from a seed in sequence. It is
+// safe for concurrent use, but the order in which concurrent callers receive
+// the streams is not deterministic: use Derive with an explicit index when
+// it matters.
+type Splitter struct {
+	seed uint64
+
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewSplitter returns a splitter of the streams derived from `seed`.
+func NewSplitter(seed uint64) *Splitter {
+	return &Splitter{seed: seed}
+}
+
+// Split returns a random number generator for the next stream.
+func (s *Splitter) Split() *rand.Rand {
+	s.mu.Lock()
+	i := s.next
+	s.next++
+	s.mu.Unlock()
+	return New(s.seed, i)
+}
@@ -0,0 +1,41 @@
+package sampler
+
+import (
+	"log"
+
+	"github.com/rlouf/gmc/trace"
+)
+
+// A BlackBox wraps a log-probability density that was not built with gmc,
+// for instance any of gonum's multivariate distributions, so that it can be
+// sampled with gmc's samplers. Names label each dimension of the target in
+// the trace.
+type BlackBox struct {
+	Target Target
+	Names  []string
+}
+
+func (b *BlackBox) LogProb(x []float64) float64 {
+	return b.Target.LogProb(x)
+}
+
+// Sample draws numSamples samples from the target with the provided sampler
+// and returns the trace, i.e. a map between the name of each dimension and
+// the values that were sampled for it.
+func (b *BlackBox) Sample(numSamples int, s *MetropolisHastings) trace.Trace {
+	if s.NumVariables != len(b.Names) {
+		log.Panicf("the sampler is configured for %d variables, the target has %d", s.NumVariables, len(b.Names))
+	}
+	s.Target = b
+
+	batch := s.Run(numSamples)
+	samples := trace.Trace{}
+	for j, name := range b.Names {
+		samples[name] = make([]float64, numSamples)
+		for i := 0; i < numSamples; i++ {
+			samples[name][i] = batch.At(i, j)
+		}
+	}
+
+	return samples
+}
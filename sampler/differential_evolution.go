@@ -0,0 +1,218 @@
+package sampler
+
+import (
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// DifferentialEvolution is a population MCMC sampler: several chains evolve
+// together and each chain's proposals are built from the difference between
+// the current states of two other chains.
+//
+// The population adapts the scale and orientation of the proposals to the
+// target, and occasional jumps of the full difference let chains move
+// between modes. See:
+//
+// "A Markov Chain Monte Carlo version of the genetic algorithm Differential
+// Evolution" (ter Braak 2006)
+// https://doi.org/10.1007/s11222-006-8769-1
+//
+// With crossover, each proposal only moves a random subset of the
+// variables, which keeps the acceptance rate up in high dimension:
+//
+// "Accelerating Markov Chain Monte Carlo Simulation by Differential
+// Evolution with Self-Adaptive Randomized Subspace Sampling" (Vrugt et al.
+// 2009)
+// https://doi.org/10.1515/IJNSNS.2009.10.3.273
+//
+// The snooker move proposes along the line that joins the chain to another
+// chain, with a length given by the projection of the difference of two more
+// chains on this line. It explores targets whose variables are strongly
+// correlated or whose modes are far apart better than the difference move:
+//
+// "Differential Evolution Markov Chain with snooker updater and fewer
+// chains" (ter Braak & Vrugt 2008)
+// https://doi.org/10.1007/s11222-008-9104-9
+type DifferentialEvolution struct {
+	Target       Target
+	NumVariables int
+	Initial      [][]float64 // initial state of each chain, at least 3 chains
+	BurnIn       int
+	Noise        float64 // standard deviation of the noise added to proposals
+	JumpEvery    int     // every JumpEvery iterations the scale of the proposals is set to 1
+
+	// CrossoverProb is the probability that the difference move updates
+	// each variable; at least one variable is updated. All the variables
+	// are updated when it is 1.
+	CrossoverProb float64
+
+	// SnookerProb is the probability that a proposal is a snooker move
+	// rather than a difference move. The snooker move needs at least 4
+	// chains.
+	SnookerProb float64
+
+	Src *rand.Rand
+}
+
+// NewDifferentialEvolution returns a differential evolution sampler for a
+// target of dimension numVariables with reasonable defaults. The snooker
+// move is disabled, and enabled by setting SnookerProb, e.g. to 0.1 as ter
+// Braak & Vrugt (2008) do.
+func NewDifferentialEvolution(target Target, numVariables int, src *rand.Rand) *DifferentialEvolution {
+	return &DifferentialEvolution{
+		Target:        target,
+		NumVariables:  numVariables,
+		BurnIn:        1000,
+		Noise:         1e-4,
+		JumpEvery:     10,
+		CrossoverProb: 1,
+		Src:           src,
+	}
+}
+
+// Run draws numSamples samples for each chain after burn-in. It returns a
+// matrix per chain in which each row is a sample, or nil when numSamples is
+// 0.
+func (d *DifferentialEvolution) Run(numSamples int) []*mat.Dense {
+	numChains := len(d.Initial)
+	if numChains < 3 {
+		log.Panicf("differential evolution needs at least 3 chains, got %d", numChains)
+	}
+	if numSamples < 0 {
+		log.Panicf("the number of samples must be positive, got %d", numSamples)
+	}
+	if d.CrossoverProb <= 0 || d.CrossoverProb > 1 {
+		log.Panicf("the crossover probability must be in (0, 1], got %f", d.CrossoverProb)
+	}
+	if d.SnookerProb < 0 || d.SnookerProb > 1 {
+		log.Panicf("the snooker probability must be in [0, 1], got %f", d.SnookerProb)
+	}
+	if d.SnookerProb > 0 && numChains < 4 {
+		log.Panicf("the snooker move needs at least 4 chains, got %d", numChains)
+	}
+
+	states := make([][]float64, numChains)
+	logProbs := make([]float64, numChains)
+	for c, initial := range d.Initial {
+		if len(initial) != d.NumVariables {
+			log.Panicf("needed %d initial points for chain %d, got %d", d.NumVariables, c, len(initial))
+		}
+		states[c] = append([]float64(nil), initial...)
+		logProbs[c] = d.Target.LogProb(states[c])
+	}
+	// The state of the chains is not kept from one run to the other, so
+	// there is no point in burning in.
+	if numSamples == 0 {
+		return nil
+	}
+
+	batches := make([]*mat.Dense, numChains)
+	for c := range batches {
+		batches[c] = mat.NewDense(numSamples, d.NumVariables, nil)
+	}
+
+	proposed := make([]float64, d.NumVariables)
+	for i := 0; i < d.BurnIn+numSamples; i++ {
+		jump := d.JumpEvery > 0 && (i+1)%d.JumpEvery == 0
+
+		for c := range states {
+			// The log of the ratio of the proposal densities, which is not
+			// 0 for the snooker move.
+			var logCorrection float64
+			if d.SnookerProb > 0 && d.Src.Float64() < d.SnookerProb {
+				logCorrection = d.snooker(proposed, states, c)
+			} else {
+				d.difference(proposed, states, c, jump)
+			}
+			proposedLogProb := d.Target.LogProb(proposed)
+			if math.Log(d.Src.Float64()) < proposedLogProb-logProbs[c]+logCorrection {
+				copy(states[c], proposed)
+				logProbs[c] = proposedLogProb
+			}
+			if i >= d.BurnIn {
+				batches[c].SetRow(i-d.BurnIn, states[c])
+			}
+		}
+	}
+
+	return batches
+}
+
+// difference sets proposed to the state of chain c moved by the difference
+// between the states of two other chains, along a random subset of the
+// variables. The scale of the move is optimal for a normal target, or 1 for
+// a jump.
+func (d *DifferentialEvolution) difference(proposed []float64, states [][]float64, c int, jump bool) {
+	copy(proposed, states[c])
+	updated := make([]int, 0, d.NumVariables)
+	for j := range proposed {
+		if d.CrossoverProb == 1 || d.Src.Float64() < d.CrossoverProb {
+			updated = append(updated, j)
+		}
+	}
+	if len(updated) == 0 {
+		updated = append(updated, d.Src.Intn(d.NumVariables))
+	}
+
+	gamma := 2.38 / math.Sqrt(2*float64(len(updated)))
+	if jump {
+		gamma = 1
+	}
+	others := d.pickOthers(c, len(states), 2)
+	r1, r2 := states[others[0]], states[others[1]]
+	for _, j := range updated {
+		noise := d.Noise * d.Src.NormFloat64()
+		proposed[j] += gamma*(r1[j]-r2[j]) + noise
+	}
+}
+
+// snooker sets proposed to the state x of chain c moved along the line that
+// joins it to the state z of another chain, by the difference between the
+// projections on this line of the states of two more chains. It returns the
+// log of the ratio of the proposal densities, (d-1) log(|x' - z| / |x - z|).
+func (d *DifferentialEvolution) snooker(proposed []float64, states [][]float64, c int) float64 {
+	others := d.pickOthers(c, len(states), 3)
+	x, z, r1, r2 := states[c], states[others[0]], states[others[1]], states[others[2]]
+
+	direction := make([]float64, len(x))
+	floats.SubTo(direction, x, z)
+	distance := floats.Norm(direction, 2)
+	copy(proposed, x)
+	if distance == 0 {
+		return 0
+	}
+	floats.Scale(1/distance, direction)
+
+	gamma := 1.2 + d.Src.Float64()
+	projection := floats.Dot(r1, direction) - floats.Dot(r2, direction)
+	floats.AddScaled(proposed, gamma*projection, direction)
+
+	proposedDistance := floats.Distance(proposed, z, 2)
+	return float64(len(x)-1) * (math.Log(proposedDistance) - math.Log(distance))
+}
+
+// pickOthers returns the indices of k distinct chains that differ from c.
+func (d *DifferentialEvolution) pickOthers(c, numChains, k int) []int {
+	picked := make([]int, 0, k)
+	for len(picked) < k {
+		r := d.Src.Intn(numChains)
+		if r == c || contains(picked, r) {
+			continue
+		}
+		picked = append(picked, r)
+	}
+	return picked
+}
+
+func contains(indices []int, i int) bool {
+	for _, j := range indices {
+		if i == j {
+			return true
+		}
+	}
+	return false
+}
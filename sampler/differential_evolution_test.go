@@ -0,0 +1,60 @@
+package sampler
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestDifferentialEvolution(t *testing.T) {
+	moves := map[string]func(d *DifferentialEvolution){
+		"difference": func(d *DifferentialEvolution) {},
+		"crossover":  func(d *DifferentialEvolution) { d.CrossoverProb = 0.5 },
+		"snooker":    func(d *DifferentialEvolution) { d.SnookerProb = 0.5 },
+	}
+	for name, configure := range moves {
+		src := rand.New(rand.NewSource(1))
+		d := NewDifferentialEvolution(standardNormal{}, 3, src)
+		configure(d)
+		d.Initial = make([][]float64, 8)
+		for c := range d.Initial {
+			d.Initial[c] = []float64{src.NormFloat64(), src.NormFloat64(), src.NormFloat64()}
+		}
+		chains := d.Run(2000)
+
+		for j := 0; j < 3; j++ {
+			var draws []float64
+			for _, chain := range chains {
+				draws = append(draws, mat.Col(nil, j, chain)...)
+			}
+			mean, variance := stat.MeanVariance(draws, nil)
+			if math.Abs(mean) > 0.15 || math.Abs(variance-1) > 0.15 {
+				t.Errorf("%s: variable %d has mean %f and variance %f, want 0 and 1", name, j, mean, variance)
+			}
+		}
+	}
+}
+
+func TestDifferentialEvolutionZeroSamples(t *testing.T) {
+	d := NewDifferentialEvolution(standardNormal{}, 2, rand.New(rand.NewSource(1)))
+	d.Initial = [][]float64{{0, 0}, {1, 0}, {0, 1}}
+	if samples := d.Run(0); samples != nil {
+		t.Errorf("expected no samples, got %v", samples)
+	}
+}
+
+func TestDifferentialEvolutionSnookerChains(t *testing.T) {
+	d := NewDifferentialEvolution(standardNormal{}, 2, rand.New(rand.NewSource(1)))
+	d.Initial = [][]float64{{0, 0}, {1, 0}, {0, 1}}
+	d.SnookerProb = 0.1
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	d.Run(10)
+}
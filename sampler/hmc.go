@@ -0,0 +1,192 @@
+package sampler
+
+import (
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// AdaptiveHMC is a Hamiltonian Monte Carlo sampler whose trajectory length
+// is drawn uniformly at random for every sample, between 0 and
+// TrajectoryLength. Jittering the length avoids the periodic behaviour of
+// fixed-length trajectories without the bookkeeping of NUTS, so that each
+// iteration has a predictable cost.
+//
+// The step size is adapted during the warmup phase with the dual averaging
+// algorithm so that the average acceptance probability matches
+// TargetAcceptance:
+//
+// "The No-U-Turn Sampler" (Hoffman & Gelman 2014), section 3.2
+// https://arxiv.org/abs/1111.4246
+type AdaptiveHMC struct {
+	Target           GradTarget
+	NumVariables     int
+	Initial          []float64
+	StepSize         float64
+	TrajectoryLength float64
+	NumWarmup        int
+	TargetAcceptance float64
+
+	// AcceptanceRate is the average acceptance probability of the
+	// proposals made after warmup during the last run.
+	AcceptanceRate float64
+
+	Src *rand.Rand
+
+	warm bool // the step size was tuned by a previous run, see WarmRestart
+}
+
+// NewAdaptiveHMC returns an adaptive HMC sampler for a target of dimension
+// numVariables with reasonable defaults.
+func NewAdaptiveHMC(target GradTarget, numVariables int, src *rand.Rand) *AdaptiveHMC {
+	return &AdaptiveHMC{
+		Target:           target,
+		NumVariables:     numVariables,
+		StepSize:         0.1,
+		TrajectoryLength: 1,
+		NumWarmup:        1000,
+		TargetAcceptance: 0.8,
+		Src:              src,
+	}
+}
+
+// HMCState contains the parameters of AdaptiveHMC that are tuned during
+// warmup. It can be encoded with encoding/gob to be stored or sent to
+// another process.
+type HMCState struct {
+	StepSize         float64
+	TrajectoryLength float64
+}
+
+// State returns the current value of the tuned parameters.
+func (h *AdaptiveHMC) State() HMCState {
+	return HMCState{StepSize: h.StepSize, TrajectoryLength: h.TrajectoryLength}
+}
+
+// Restore sets the tuned parameters to a previously saved state.
+func (h *AdaptiveHMC) Restore(state HMCState) {
+	h.StepSize = state.StepSize
+	h.TrajectoryLength = state.TrajectoryLength
+}
+
+// WarmRestart prepares the sampler to fit a target that differs little
+// from the one of the run that produced the state, e.g. a model refitted
+// every day on slightly more data. The tuned parameters are restored and the
+// warmup is shortened to numWarmup iterations, during which the adaptation
+// of the step size starts from the restored value instead of first trying
+// larger steps. Start the chain from the previous posterior as well, see
+// Model.InitialFromTrace.
+func (h *AdaptiveHMC) WarmRestart(state HMCState, numWarmup int) {
+	if numWarmup < 0 {
+		log.Panicf("the number of warmup iterations must be positive, got %d", numWarmup)
+	}
+	h.Restore(state)
+	h.NumWarmup = numWarmup
+	h.warm = true
+}
+
+// Run draws numSamples samples after the warmup phase. Each row of the
+// returned matrix is a sample. When numSamples is 0 Run only tunes the step
+// size during warmup, e.g. to save the state, and returns nil.
+func (h *AdaptiveHMC) Run(numSamples int) *mat.Dense {
+	if numSamples < 0 {
+		log.Panicf("the number of samples must be positive, got %d", numSamples)
+	}
+	if h.Initial == nil {
+		log.Panicf("you need to provide initial values to the sampler: specify the value of the `Initial` parameter.")
+	}
+	if len(h.Initial) != h.NumVariables {
+		log.Panicf("needed %d initial points, got %d: please change the value of the `Initial` parameter", h.NumVariables, len(h.Initial))
+	}
+
+	position := append([]float64(nil), h.Initial...)
+	logProb := h.Target.LogProb(position)
+
+	// Dual averaging state
+	const (
+		gamma = 0.05
+		t0    = 10
+		kappa = 0.75
+	)
+	stepSize := h.StepSize
+	mu := math.Log(10 * stepSize)
+	if h.warm {
+		mu = math.Log(stepSize)
+	}
+	var averageError, logAverageStepSize float64
+
+	for i := 1; i <= h.NumWarmup; i++ {
+		var acceptProb float64
+		position, logProb, acceptProb = h.transition(position, logProb, stepSize)
+
+		w := 1 / (float64(i) + t0)
+		averageError = (1-w)*averageError + w*(h.TargetAcceptance-acceptProb)
+		logStepSize := mu - math.Sqrt(float64(i))/gamma*averageError
+		stepSize = math.Exp(logStepSize)
+		eta := math.Pow(float64(i), -kappa)
+		logAverageStepSize = eta*logStepSize + (1-eta)*logAverageStepSize
+	}
+	if h.NumWarmup > 0 {
+		stepSize = math.Exp(logAverageStepSize)
+		h.StepSize = stepSize
+	}
+
+	if numSamples == 0 {
+		return nil
+	}
+
+	batch := mat.NewDense(numSamples, h.NumVariables, nil)
+	var totalAccept float64
+	for i := 0; i < numSamples; i++ {
+		var acceptProb float64
+		position, logProb, acceptProb = h.transition(position, logProb, stepSize)
+		totalAccept += acceptProb
+		batch.SetRow(i, position)
+	}
+	h.AcceptanceRate = totalAccept / float64(numSamples)
+
+	return batch
+}
+
+// transition integrates a trajectory of random length from the current
+// position and accepts or rejects its end point. It returns the new
+// position, its log-probability and the acceptance probability.
+func (h *AdaptiveHMC) transition(position []float64, logProb, stepSize float64) ([]float64, float64, float64) {
+	n := len(position)
+	momentum := make([]float64, n)
+	for i := range momentum {
+		momentum[i] = h.Src.NormFloat64()
+	}
+	energy := -logProb + floats.Dot(momentum, momentum)/2
+
+	length := (1 - h.Src.Float64()) * h.TrajectoryLength
+	numSteps := int(math.Ceil(length / stepSize))
+
+	proposed := append([]float64(nil), position...)
+	grad := make([]float64, n)
+	h.Target.Grad(grad, proposed)
+	for step := 0; step < numSteps; step++ {
+		floats.AddScaled(momentum, stepSize/2, grad)
+		floats.AddScaled(proposed, stepSize, momentum)
+		h.Target.Grad(grad, proposed)
+		floats.AddScaled(momentum, stepSize/2, grad)
+	}
+
+	proposedLogProb := h.Target.LogProb(proposed)
+	proposedEnergy := -proposedLogProb + floats.Dot(momentum, momentum)/2
+	acceptProb := math.Min(1, math.Exp(energy-proposedEnergy))
+	if math.IsNaN(acceptProb) {
+		acceptProb = 0
+	}
+
+	if h.Src.Float64() < acceptProb {
+		return proposed, proposedLogProb, acceptProb
+	}
+	// The target may have been evaluated elsewhere, so we leave it in the
+	// state of the current position.
+	h.Target.LogProb(position)
+	return position, logProb, acceptProb
+}
@@ -0,0 +1,53 @@
+package sampler
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// standardNormal is a standard normal target of any dimension.
+type standardNormal struct{}
+
+func (standardNormal) LogProb(x []float64) float64 {
+	var logProb float64
+	for _, v := range x {
+		logProb -= v * v / 2
+	}
+	return logProb
+}
+
+func (standardNormal) Grad(grad, x []float64) {
+	for i, v := range x {
+		grad[i] = -v
+	}
+}
+
+func TestAdaptiveHMC(t *testing.T) {
+	h := NewAdaptiveHMC(standardNormal{}, 2, rand.New(rand.NewSource(1)))
+	h.Initial = []float64{3, -3}
+	samples := h.Run(4000)
+
+	for j := 0; j < 2; j++ {
+		mean, variance := stat.MeanVariance(mat.Col(nil, j, samples), nil)
+		if math.Abs(mean) > 0.15 || math.Abs(variance-1) > 0.15 {
+			t.Errorf("variable %d has mean %f and variance %f, want 0 and 1", j, mean, variance)
+		}
+	}
+}
+
+// TestAdaptiveHMCZeroSamples checks that a run without samples tunes the
+// step size and returns nil.
+func TestAdaptiveHMCZeroSamples(t *testing.T) {
+	h := NewAdaptiveHMC(standardNormal{}, 2, rand.New(rand.NewSource(1)))
+	h.Initial = []float64{0, 0}
+	if samples := h.Run(0); samples != nil {
+		t.Errorf("expected no samples, got %v", samples)
+	}
+	if h.StepSize == 0.1 {
+		t.Error("the step size was not tuned")
+	}
+}
@@ -5,3 +5,17 @@ type Sampler interface {
 	Tune()
 	Initialize()
 }
+
+// A Target is a log-probability density function, up to a constant, that the
+// samplers can draw from. gmc models and gonum's distmv.LogProber values
+// satisfy this interface.
+type Target interface {
+	LogProb(x []float64) float64
+}
+
+// A GradTarget is a Target whose log-probability can be differentiated, as
+// required by gradient-based samplers.
+type GradTarget interface {
+	Target
+	Grad(grad, x []float64)
+}
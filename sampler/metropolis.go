@@ -3,6 +3,7 @@ package sampler
 import (
 	"log"
 
+	"golang.org/x/exp/rand"
 	"gonum.org/v1/gonum/mat"
 	"gonum.org/v1/gonum/stat/samplemv"
 )
@@ -12,6 +13,28 @@ type MetropolisHastings struct {
 	NumVariables int
 }
 
+// NewMetropolisHastings returns a Metropolis-Hastings sampler for a target
+// of dimension numVariables with a normal proposal distribution.
+func NewMetropolisHastings(target Target, numVariables int, src *rand.Rand) *MetropolisHastings {
+	sigmaSym := mat.NewSymDense(numVariables, nil)
+	for i := 0; i < numVariables; i++ {
+		sigmaSym.SetSym(i, i, 0.05)
+	}
+	proposal, ok := samplemv.NewProposalNormal(sigmaSym, src)
+	if !ok {
+		log.Panicf("could not build the proposal distribution for %d variables", numVariables)
+	}
+
+	return &MetropolisHastings{
+		MetropolisHastingser: &samplemv.MetropolisHastingser{
+			BurnIn:   1000,
+			Proposal: proposal,
+			Src:      src,
+			Target:   target},
+		NumVariables: numVariables,
+	}
+}
+
 func (m *MetropolisHastings) Run(numSamples int) *mat.Dense {
 	if m.Initial == nil {
 		log.Panicf("you need to provide initial values to the sampler: run <sampler>.Tune() for automatic initialization, or specify the value of the `Initial` parameter.")
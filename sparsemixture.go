@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/rlouf/gmc/node"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// SparseMixtureConfig sets the structure of a sparse finite mixture.
+type SparseMixtureConfig struct {
+	// NumComponents is the number of components of the mixture, an upper
+	// bound on the number of clusters. It defaults to 10.
+	NumComponents int
+
+	// Concentration is the parameter of the symmetric Dirichlet prior on the
+	// weights. Values much smaller than 1 empty the superfluous components.
+	// It defaults to 0.01.
+	Concentration float64
+}
+
+// A SparseMixture is an overfitted mixture of normal distributions, used to
+// estimate the number of clusters in the data without reversible jumps or a
+// Dirichlet process. The mixture has more components than there are
+// plausible clusters, and the sparse prior on the weights leaves the
+// superfluous components empty; the number of clusters is estimated by the
+// number of components that observations are allocated to:
+//
+// "Model-based clustering based on sparse finite Gaussian mixtures"
+// (Malsiner-Walli et al. 2016)
+// https://doi.org/10.1007/s11222-014-9500-2
+//
+// The data are standardized with their mean and standard deviation and, on
+// this scale:
+//
+//	weight     ~ Dirichlet(Concentration, ..., Concentration)
+//	mu_k       ~ Normal(0, 2)
+//	variance_k ~ InverseGamma(2.5, 1)
+//	y_i        ~ sum_k weight_k * Normal(mu_k, sqrt(variance_k))
+//
+// The allocation of the observations to the components is marginalized out
+// of the likelihood. The components are exchangeable, so their labels can
+// switch during sampling; the number of occupied components does not depend
+// on the labels.
+//
+// Superfluous components empty slowly with random-walk samplers, which have
+// to drive their weights towards 0 one small step at a time: use a long
+// burn-in, and check that the number of clusters is stable across runs.
+type SparseMixture struct {
+	*Model
+	Weights      *node.Dirichlet
+	Mus          []*node.Normal
+	Variances    []*node.InverseGamma
+	observations []*node.NormalMixture
+}
+
+// NewSparseMixture builds the sparse finite mixture of the values.
+func NewSparseMixture(values []float64, config SparseMixtureConfig) *SparseMixture {
+	if config.NumComponents == 0 {
+		config.NumComponents = 10
+	}
+	if config.Concentration == 0 {
+		config.Concentration = 0.01
+	}
+	if config.NumComponents < 2 {
+		log.Panicf("the mixture needs at least 2 components, got %d", config.NumComponents)
+	}
+	if config.Concentration < 0 {
+		log.Panicf("The concentration must be strictly positive, got %f", config.Concentration)
+	}
+	if len(values) < 2 {
+		log.Panicf("needed at least 2 values, got %d", len(values))
+	}
+	center, scale := stat.MeanStdDev(values, nil)
+	if scale == 0 {
+		log.Panicf("the values are constant and cannot be clustered")
+	}
+
+	sm := &SparseMixture{Model: NewModel()}
+	m := sm.Model
+
+	alphas := make([]node.Var, config.NumComponents)
+	for k := range alphas {
+		alphas[k] = m.Constant(config.Concentration)
+	}
+	sm.Weights = m.Dirichlet("weight", alphas)
+
+	mus := make([]node.Var, config.NumComponents)
+	sigmas := make([]node.Var, config.NumComponents)
+	for k := range mus {
+		mu := m.Normal(fmt.Sprintf("mu_%d", k), m.Constant(0), m.Constant(2))
+		variance := m.InverseGamma(fmt.Sprintf("variance_%d", k), m.Constant(2.5), m.Constant(1))
+		sm.Mus = append(sm.Mus, mu)
+		sm.Variances = append(sm.Variances, variance)
+		mus[k], sigmas[k] = mu, m.Sqrt(variance)
+	}
+
+	weights := sm.Weights.Components()
+	for i, value := range values {
+		y := m.NormalMixture(fmt.Sprintf("y_%d", i), weights, mus, sigmas)
+		m.Observe(y, (value-center)/scale)
+		sm.observations = append(sm.observations, y)
+	}
+
+	return sm
+}
+
+// Initial returns initial values of the stochastic variables for the
+// samplers: equal weights, and means spread over the range of the
+// standardized data so that the components start apart.
+func (sm *SparseMixture) Initial() []float64 {
+	numComponents := len(sm.Mus)
+	initial := make([]float64, 0, 3*numComponents)
+	for range sm.Weights.Gammas {
+		initial = append(initial, 1)
+	}
+	for k := range sm.Mus {
+		mu := -2 + 4*float64(k)/float64(numComponents-1)
+		initial = append(initial, mu, 0.5)
+	}
+	return initial
+}
+
+// OccupiedComponents returns, for each sample in the trace, the number of
+// components that at least one observation is allocated to. The allocation
+// of each observation is drawn from its posterior distribution given the
+// parameters of the sample.
+func (sm *SparseMixture) OccupiedComponents(trace map[string][]float64) []float64 {
+	numSamples := sm.traceSize(trace)
+	occupied := make([]float64, numSamples)
+	probs := make([]float64, len(sm.Mus))
+	for s := range occupied {
+		for k, g := range sm.Weights.Gammas {
+			sm.setFromTrace(g, trace, s)
+			sm.setFromTrace(sm.Mus[k], trace, s)
+			sm.setFromTrace(sm.Variances[k], trace, s)
+		}
+
+		counts := make([]int, len(sm.Mus))
+		for _, y := range sm.observations {
+			logProbs := y.ComponentLogProbs()
+			norm := floats.LogSumExp(logProbs)
+			for k, lp := range logProbs {
+				probs[k] = math.Exp(lp - norm)
+			}
+			counts[int(distuv.NewCategorical(probs, sm.Src).Rand())]++
+		}
+		for _, c := range counts {
+			if c > 0 {
+				occupied[s]++
+			}
+		}
+	}
+	return occupied
+}
+
+// NumClusters returns the posterior probability of each number of clusters,
+// estimated by the number of occupied components, along with its mode, the
+// usual point estimate.
+func (sm *SparseMixture) NumClusters(trace map[string][]float64) (int, map[int]float64) {
+	occupied := sm.OccupiedComponents(trace)
+	probs := make(map[int]float64)
+	for _, o := range occupied {
+		probs[int(o)] += 1 / float64(len(occupied))
+	}
+	mode := 0
+	for n, p := range probs {
+		if p > probs[mode] || (p == probs[mode] && n < mode) {
+			mode = n
+		}
+	}
+	return mode, probs
+}
+
+func (sm *SparseMixture) setFromTrace(variable node.RandVar, trace map[string][]float64, s int) {
+	draws, ok := trace[variable.Name()]
+	if !ok {
+		log.Panicf("The trace is missing variable %s", variable.Name())
+	}
+	if err := variable.SetValue(draws[s]); err != nil {
+		log.Panicf("invalid sample %d of %s: %v", s, variable.Name(), err)
+	}
+}
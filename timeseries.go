@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/rlouf/gmc/node"
+	"gonum.org/v1/gonum/stat"
+)
+
+// A Seasonality is a periodic component of a time series, represented by a
+// Fourier series with Order pairs of sine and cosine terms.
+type Seasonality struct {
+	Name   string
+	Period float64 // in the unit of the time axis, e.g. 7 for a weekly pattern on daily data
+	Order  int
+	Scale  float64 // prior standard deviation of the Fourier coefficients
+}
+
+// A Holiday is an effect that is added to the series at the given times.
+type Holiday struct {
+	Name  string
+	Times []float64
+	Scale float64 // prior standard deviation of the effect
+}
+
+// TimeSeriesConfig describes the components of a structural time series.
+type TimeSeriesConfig struct {
+	TrendScale    float64 // prior standard deviation of the intercept and slope
+	Seasonalities []Seasonality
+	Holidays      []Holiday
+	Noise         float64 // standard deviation of the observation noise
+}
+
+// A TimeSeries is a model of a series that is the sum of a linear trend,
+// seasonal components and holiday effects, observed with normal noise:
+//
+// y(t) = intercept + slope * t + sum_s seasonality_s(t) + sum_h holiday_h(t) + noise
+//
+// This is the additive decomposition popularized by Prophet:
+//
+// "Forecasting at scale" (Taylor & Letham 2017)
+// https://doi.org/10.7287/peerj.preprints.3190v2
+//
+// The trend's parameters are named "intercept" and "slope", the Fourier
+// coefficients of a seasonality "<name>_cos_<k>" and "<name>_sin_<k>" and the
+// holiday effects take the name of the holiday. The observations are named
+// "y_<i>" after their position in the series.
+type TimeSeries struct {
+	*Model
+	config TimeSeriesConfig
+}
+
+// NewTimeSeries builds the structural time series model of the values
+// observed at the given times.
+func NewTimeSeries(times, values []float64, config TimeSeriesConfig) *TimeSeries {
+	if len(times) != len(values) {
+		log.Panicf("needed %d values, got %d", len(times), len(values))
+	}
+	if config.TrendScale <= 0 {
+		log.Panicf("The trend scale must be strictly positive, got %f", config.TrendScale)
+	}
+	if config.Noise <= 0 {
+		log.Panicf("The noise must be strictly positive, got %f", config.Noise)
+	}
+	for _, s := range config.Seasonalities {
+		if s.Period <= 0 || s.Order < 1 || s.Scale <= 0 {
+			log.Panicf("seasonality %s needs a positive period, order and scale", s.Name)
+		}
+	}
+	for _, h := range config.Holidays {
+		if h.Scale <= 0 {
+			log.Panicf("holiday %s needs a strictly positive scale", h.Name)
+		}
+	}
+
+	ts := &TimeSeries{Model: NewModel(), config: config}
+	m := ts.Model
+
+	trendScale := m.Constant(config.TrendScale)
+	intercept := m.Normal("intercept", m.Constant(0), trendScale)
+	slope := m.Normal("slope", m.Constant(0), trendScale)
+
+	type term struct {
+		coefficient node.Var
+		feature     func(t float64) float64
+	}
+	var terms []term
+	for _, s := range config.Seasonalities {
+		scale := m.Constant(s.Scale)
+		for k := 1; k <= s.Order; k++ {
+			cos, sin := fourier(s.Period, k)
+			terms = append(terms,
+				term{m.Normal(fmt.Sprintf("%s_cos_%d", s.Name, k), m.Constant(0), scale), cos},
+				term{m.Normal(fmt.Sprintf("%s_sin_%d", s.Name, k), m.Constant(0), scale), sin},
+			)
+		}
+	}
+	holidays := make([]node.Var, len(config.Holidays))
+	for i, h := range config.Holidays {
+		holidays[i] = m.Normal(h.Name, m.Constant(0), m.Constant(h.Scale))
+	}
+
+	noise := m.Constant(config.Noise)
+	for i, t := range times {
+		mean := m.Sum(intercept, m.Prod(slope, m.Constant(t)))
+		for _, c := range terms {
+			mean = m.Sum(mean, m.Prod(c.coefficient, m.Constant(c.feature(t))))
+		}
+		for j, h := range config.Holidays {
+			if isHoliday(h, t) {
+				mean = m.Sum(mean, holidays[j])
+			}
+		}
+		y := m.Normal(fmt.Sprintf("y_%d", i), mean, noise)
+		m.Observe(y, values[i])
+	}
+
+	return ts
+}
+
+// Components returns the posterior mean of the contribution of each
+// component of the series at the given times: "trend", and the name of each
+// seasonality and holiday.
+func (ts *TimeSeries) Components(trace map[string][]float64, times []float64) map[string][]float64 {
+	traceSize := ts.traceSize(trace)
+
+	components := make(map[string][]float64)
+	for _, t := range times {
+		sums := make(map[string]float64)
+		for loc := 0; loc < traceSize; loc++ {
+			for name, value := range ts.components(trace, loc, t) {
+				sums[name] += value
+			}
+		}
+		for name, sum := range sums {
+			components[name] = append(components[name], sum/float64(traceSize))
+		}
+	}
+
+	return components
+}
+
+// Forecast returns the median and the central interval that contains a
+// proportion `prob` of the posterior predictive distribution of the series
+// at each of the given times.
+//
+// The intervals are computed from numSamples samples, so they account for
+// the uncertainty on the components as well as for the observation noise.
+func (ts *TimeSeries) Forecast(numSamples int, trace map[string][]float64, times []float64, prob float64) []Interval {
+	if prob <= 0 || prob >= 1 {
+		log.Panicf("the interval probability must be in (0,1), got %f", prob)
+	}
+	traceSize := ts.traceSize(trace)
+
+	intervals := make([]Interval, len(times))
+	samples := make([]float64, numSamples)
+	for i, t := range times {
+		for n := range samples {
+			var mean float64
+			for _, value := range ts.components(trace, ts.Src.Intn(traceSize), t) {
+				mean += value
+			}
+			samples[n] = mean + ts.config.Noise*ts.Src.NormFloat64()
+		}
+		sort.Float64s(samples)
+		intervals[i] = Interval{
+			Lower:  stat.Quantile((1-prob)/2, stat.Empirical, samples, nil),
+			Median: stat.Quantile(0.5, stat.Empirical, samples, nil),
+			Upper:  stat.Quantile((1+prob)/2, stat.Empirical, samples, nil),
+		}
+	}
+
+	return intervals
+}
+
+// components returns the contribution of each component at time t for the
+// sample at position loc in the trace.
+func (ts *TimeSeries) components(trace map[string][]float64, loc int, t float64) map[string]float64 {
+	values := map[string]float64{
+		"trend": trace["intercept"][loc] + trace["slope"][loc]*t,
+	}
+	for _, s := range ts.config.Seasonalities {
+		var value float64
+		for k := 1; k <= s.Order; k++ {
+			cos, sin := fourier(s.Period, k)
+			value += trace[fmt.Sprintf("%s_cos_%d", s.Name, k)][loc] * cos(t)
+			value += trace[fmt.Sprintf("%s_sin_%d", s.Name, k)][loc] * sin(t)
+		}
+		values[s.Name] = value
+	}
+	for _, h := range ts.config.Holidays {
+		if isHoliday(h, t) {
+			values[h.Name] = trace[h.Name][loc]
+		} else {
+			values[h.Name] = 0
+		}
+	}
+	return values
+}
+
+// fourier returns the k-th cosine and sine terms of a Fourier series of the
+// given period.
+func fourier(period float64, k int) (cos, sin func(t float64) float64) {
+	omega := 2 * math.Pi * float64(k) / period
+	cos = func(t float64) float64 { return math.Cos(omega * t) }
+	sin = func(t float64) float64 { return math.Sin(omega * t) }
+	return cos, sin
+}
+
+func isHoliday(h Holiday, t float64) bool {
+	for _, ht := range h.Times {
+		if ht == t {
+			return true
+		}
+	}
+	return false
+}
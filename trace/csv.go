@@ -0,0 +1,133 @@
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A Format controls how the exports of the package write numbers.
+//
+// Start from DefaultFormat and change the fields you need, e.g. for a locale
+// that uses a decimal comma:
+//
+//	f := trace.DefaultFormat
+//	f.Delimiter, f.Decimal = ';', ','
+type Format struct {
+	Precision int     // number of digits after the decimal point, 0 for the fewest digits that represent the value exactly
+	SciAbove  float64 // absolute values >= SciAbove are written in scientific notation, 0 to disable
+	SciBelow  float64 // non-zero absolute values < SciBelow are written in scientific notation, 0 to disable
+	Delimiter rune    // field delimiter, ',' if zero
+	Decimal   rune    // decimal separator, '.' if zero
+}
+
+// DefaultFormat writes numbers exactly, with a dot as decimal separator, and
+// switches to scientific notation for very large and very small values.
+var DefaultFormat = Format{
+	SciAbove:  1e21,
+	SciBelow:  1e-4,
+	Delimiter: ',',
+	Decimal:   '.',
+}
+
+// Float formats a number. The output does not depend on the locale of the
+// host.
+func (f Format) Float(x float64) string {
+	abs := math.Abs(x)
+	fmtByte := byte('f')
+	if (f.SciAbove > 0 && abs >= f.SciAbove) || (f.SciBelow > 0 && abs != 0 && abs < f.SciBelow) {
+		fmtByte = 'e'
+	}
+	precision := f.Precision
+	if precision <= 0 {
+		precision = -1
+	}
+	s := strconv.FormatFloat(x, fmtByte, precision, 64)
+	if d := f.decimal(); d != '.' {
+		s = strings.Replace(s, ".", string(d), 1)
+	}
+	return s
+}
+
+func (f Format) delimiter() rune {
+	if f.Delimiter == 0 {
+		return ','
+	}
+	return f.Delimiter
+}
+
+func (f Format) decimal() rune {
+	if f.Decimal == 0 {
+		return '.'
+	}
+	return f.Decimal
+}
+
+func (f Format) newWriter(w io.Writer) (*csv.Writer, error) {
+	if f.delimiter() == f.decimal() {
+		return nil, fmt.Errorf("the delimiter and the decimal separator must differ, both are %q", f.delimiter())
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = f.delimiter()
+	return writer, nil
+}
+
+// WriteCSV writes the samples of the trace with one column per variable, in
+// alphabetical order, and one row per sample. The first row contains the
+// names of the variables.
+func (t Trace) WriteCSV(w io.Writer, f Format) error {
+	writer, err := f.newWriter(w)
+	if err != nil {
+		return err
+	}
+
+	names := t.Names()
+	numSamples := 0
+	for _, name := range names {
+		if n := len(t[name]); n > numSamples {
+			numSamples = n
+		}
+	}
+
+	if err := writer.Write(names); err != nil {
+		return err
+	}
+	record := make([]string, len(names))
+	for i := 0; i < numSamples; i++ {
+		for j, name := range names {
+			if i < len(t[name]) {
+				record[j] = f.Float(t[name][i])
+			} else {
+				record[j] = ""
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSummaryCSV writes the summaries with one row per variable.
+func WriteSummaryCSV(w io.Writer, summaries []Summary, f Format) error {
+	writer, err := f.newWriter(w)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Write([]string{"name", "mean", "sd", "median", "mode"}); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		record := []string{s.Name, f.Float(s.Mean), f.Float(s.StdDev), f.Float(s.Median), f.Float(s.Mode)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,118 @@
+package trace
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// A Density is a gaussian kernel density estimate of the distribution the
+// samples of a variable were drawn from. It can be evaluated anywhere, unlike
+// the samples themselves, e.g. to plot a smooth density or to use a posterior
+// as the prior of another model.
+type Density struct {
+	Draws     []float64 // sorted samples
+	Bandwidth float64   // standard deviation of the kernels
+}
+
+// Density returns the kernel density estimate of the samples of a variable.
+func (t Trace) Density(name string) *Density {
+	samples, ok := t[name]
+	if !ok {
+		log.Panicf("The trace is missing variable %s", name)
+	}
+	return NewDensity(samples)
+}
+
+// NewDensity returns the kernel density estimate of the samples. The
+// bandwidth is chosen with Silverman's rule of thumb, in its version that is
+// robust to heavy tails and to skewness:
+//
+// h = 0.9 * min(std, IQR / 1.34) * n^(-1/5)
+//
+// It oversmooths multimodal distributions; set Bandwidth to change it.
+func NewDensity(samples []float64) *Density {
+	if len(samples) == 0 {
+		log.Panicf("cannot estimate the density of an empty sample")
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	spread := stat.StdDev(sorted, nil)
+	iqr := stat.Quantile(0.75, stat.Empirical, sorted, nil) - stat.Quantile(0.25, stat.Empirical, sorted, nil)
+	if iqr > 0 && iqr/1.34 < spread {
+		spread = iqr / 1.34
+	}
+	if spread == 0 || math.IsNaN(spread) {
+		spread = 1
+	}
+
+	return &Density{
+		Draws:     sorted,
+		Bandwidth: 0.9 * spread * math.Pow(float64(len(sorted)), -0.2),
+	}
+}
+
+// Prob returns the value of the density at x.
+func (d *Density) Prob(x float64) float64 {
+	return math.Exp(d.LogProb(x))
+}
+
+// LogProb returns the logarithm of the density at x.
+func (d *Density) LogProb(x float64) float64 {
+	kernel := distuv.Normal{Mu: 0, Sigma: d.Bandwidth}
+	logprobs := make([]float64, len(d.Draws))
+	for i, draw := range d.Draws {
+		logprobs[i] = kernel.LogProb(x - draw)
+	}
+	return floats.LogSumExp(logprobs) - math.Log(float64(len(d.Draws)))
+}
+
+// CDF returns the probability that the variable is lower than x.
+func (d *Density) CDF(x float64) float64 {
+	kernel := distuv.Normal{Mu: 0, Sigma: d.Bandwidth}
+	var cdf float64
+	for _, draw := range d.Draws {
+		cdf += kernel.CDF(x - draw)
+	}
+	return cdf / float64(len(d.Draws))
+}
+
+// Quantile returns the value below which the variable lies with probability
+// p, found by bisection of the CDF.
+func (d *Density) Quantile(p float64) float64 {
+	if p <= 0 || p >= 1 {
+		log.Panicf("the probability must be in (0,1), got %f", p)
+	}
+	// Almost all the mass of the kernels lies within 10 bandwidths of the
+	// extreme draws.
+	lower := d.Draws[0] - 10*d.Bandwidth
+	upper := d.Draws[len(d.Draws)-1] + 10*d.Bandwidth
+	for i := 0; i < 100 && upper-lower > 1e-9*d.Bandwidth; i++ {
+		middle := (lower + upper) / 2
+		if d.CDF(middle) < p {
+			lower = middle
+		} else {
+			upper = middle
+		}
+	}
+	return (lower + upper) / 2
+}
+
+// Rand draws a random value from the density: a draw picked at random plus
+// the noise of its kernel.
+func (d *Density) Rand(src *rand.Rand) float64 {
+	kernel := distuv.Normal{Mu: 0, Sigma: d.Bandwidth, Src: src}
+	var i int
+	if src == nil {
+		i = rand.Intn(len(d.Draws))
+	} else {
+		i = src.Intn(len(d.Draws))
+	}
+	return d.Draws[i] + kernel.Rand()
+}
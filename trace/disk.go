@@ -0,0 +1,179 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+)
+
+// diskMagic starts the files written by a DiskWriter.
+const diskMagic = "gmctrace"
+
+// A DiskWriter appends the samples of a run to a file as they are drawn, so
+// that they survive a crash of the process; see Recover.
+//
+// The file starts with a header that contains the specification of the model
+// and the names of the variables, followed by one record per sample: the
+// values of the variables in the order of the header and a CRC-32 checksum
+// of these values. A record is only written once complete, so a crash can
+// at most leave a truncated last record, which Recover ignores.
+type DiskWriter struct {
+	file   *os.File
+	buffer *bufio.Writer
+	names  []string
+	record []byte
+}
+
+// CreateDisk creates the file at path, truncating it if it exists, and
+// writes the header. `spec` describes the model; it is returned as is by
+// Recover.
+func CreateDisk(path, spec string, names []string) (*DiskWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header bytes.Buffer
+	header.WriteString(diskMagic)
+	header.WriteByte(encodingVersion)
+	writeUint(&header, len(spec))
+	header.WriteString(spec)
+	writeUint(&header, len(names))
+	for _, name := range names {
+		writeUint(&header, len(name))
+		header.WriteString(name)
+	}
+	if _, err := file.Write(header.Bytes()); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &DiskWriter{
+		file:   file,
+		buffer: bufio.NewWriter(file),
+		names:  append([]string(nil), names...),
+		record: make([]byte, 8*len(names)+4),
+	}, nil
+}
+
+// Append writes the values of the variables for one sample, in the order of
+// the names given to CreateDisk. The sample is buffered until the next call
+// to Flush.
+func (d *DiskWriter) Append(values []float64) error {
+	if len(values) != len(d.names) {
+		return fmt.Errorf("needed %d values, got %d", len(d.names), len(values))
+	}
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(d.record[8*i:], math.Float64bits(v))
+	}
+	checksum := crc32.ChecksumIEEE(d.record[:8*len(values)])
+	binary.LittleEndian.PutUint32(d.record[8*len(values):], checksum)
+	_, err := d.buffer.Write(d.record)
+	return err
+}
+
+// Flush writes the buffered samples to the file and waits until they are
+// stored on disk.
+func (d *DiskWriter) Flush() error {
+	if err := d.buffer.Flush(); err != nil {
+		return err
+	}
+	return d.file.Sync()
+}
+
+// Close flushes the buffered samples and closes the file.
+func (d *DiskWriter) Close() error {
+	if err := d.Flush(); err != nil {
+		d.file.Close()
+		return err
+	}
+	return d.file.Close()
+}
+
+// Recover reads a file written by a DiskWriter, possibly during a run that
+// crashed, and returns the samples of all the complete records along with
+// the specification of the model. A truncated or corrupted record ends the
+// trace, since nothing after it can be trusted.
+func Recover(path string) (Trace, string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	r := bytes.NewReader(content)
+
+	magic := make([]byte, len(diskMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != diskMagic {
+		return nil, "", errors.New("not a trace file")
+	}
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, "", err
+	}
+	if version != encodingVersion {
+		return nil, "", fmt.Errorf("unsupported trace encoding version %d", version)
+	}
+	spec, err := readString(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("truncated header: %v", err)
+	}
+	numNames, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("truncated header: %v", err)
+	}
+	// Each name takes at least one byte, its length.
+	if numNames > uint64(r.Len()) {
+		return nil, "", fmt.Errorf("truncated header: %v", io.ErrUnexpectedEOF)
+	}
+	names := make([]string, numNames)
+	for i := range names {
+		if names[i], err = readString(r); err != nil {
+			return nil, "", fmt.Errorf("truncated header: %v", err)
+		}
+	}
+
+	recovered := Trace{}
+	for _, name := range names {
+		recovered[name] = []float64{}
+	}
+	record := make([]byte, 8*len(names)+4)
+	for {
+		if _, err := io.ReadFull(r, record); err != nil {
+			break
+		}
+		values := record[:8*len(names)]
+		if crc32.ChecksumIEEE(values) != binary.LittleEndian.Uint32(record[len(values):]) {
+			break
+		}
+		for i, name := range names {
+			recovered[name] = append(recovered[name], math.Float64frombits(binary.LittleEndian.Uint64(values[8*i:])))
+		}
+	}
+
+	return recovered, spec, nil
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if length > uint64(r.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	s := make([]byte, length)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
@@ -0,0 +1,108 @@
+package trace
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeDisk writes the samples to a new trace file and returns its path and
+// content.
+func writeDisk(t *testing.T, dir string, samples [][]float64) (string, []byte) {
+	path := filepath.Join(dir, "trace")
+	d, err := CreateDisk(path, "x ~ Normal", []string{"x", "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range samples {
+		if err := d.Append(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, content
+}
+
+func TestRecover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gmc-disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	samples := [][]float64{{1, 2}, {3, 4}, {5, 6}}
+	path, content := writeDisk(t, dir, samples)
+	const recordSize = 2*8 + 4
+
+	recovered, spec, err := Recover(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec != "x ~ Normal" {
+		t.Errorf("recovered the specification %q", spec)
+	}
+	if expected := (Trace{"x": {1, 3, 5}, "y": {2, 4, 6}}); !reflect.DeepEqual(recovered, expected) {
+		t.Errorf("recovered %v, expected %v", recovered, expected)
+	}
+
+	// A crash in the middle of the last record loses that record only.
+	if err := ioutil.WriteFile(path, content[:len(content)-recordSize/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	recovered, _, err = Recover(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Trace{"x": {1, 3}, "y": {2, 4}}); !reflect.DeepEqual(recovered, expected) {
+		t.Errorf("recovered %v from a truncated file, expected %v", recovered, expected)
+	}
+
+	// A corrupted record ends the trace.
+	corrupted := append([]byte(nil), content...)
+	corrupted[len(content)-2*recordSize] ^= 0xff
+	if err := ioutil.WriteFile(path, corrupted, 0644); err != nil {
+		t.Fatal(err)
+	}
+	recovered, _, err = Recover(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Trace{"x": {1}, "y": {2}}); !reflect.DeepEqual(recovered, expected) {
+		t.Errorf("recovered %v from a corrupted file, expected %v", recovered, expected)
+	}
+}
+
+func TestRecoverCorruptHeader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gmc-disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path, content := writeDisk(t, dir, nil)
+	headers := map[string][]byte{
+		"magic":      []byte("notatrace"),
+		"huge spec":  append([]byte(diskMagic+"\x01"), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f),
+		"huge names": append([]byte(diskMagic+"\x01\x00"), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f),
+	}
+	for n := 0; n < len(content); n++ {
+		headers[fmt.Sprintf("truncated at %d", n)] = content[:n]
+	}
+	for name, header := range headers {
+		if err := ioutil.WriteFile(path, header, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := Recover(path); err == nil {
+			t.Errorf("%s: recovering %v returned no error", name, header)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// encodingVersion is written at the beginning of the binary encoding of a
+// trace so that the format can evolve.
+const encodingVersion = 1
+
+// MarshalBinary encodes the trace in a stable binary format: the variables
+// are written in alphabetical order as their name followed by their
+// samples, all integers and floats in little-endian order. Traces can thus
+// be stored and sent through encoding/gob and Go RPC.
+func (t Trace) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(encodingVersion)
+	writeUint(&b, len(t))
+	for _, name := range t.Names() {
+		writeUint(&b, len(name))
+		b.WriteString(name)
+		writeUint(&b, len(t[name]))
+		for _, v := range t[name] {
+			binary.Write(&b, binary.LittleEndian, math.Float64bits(v))
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a trace encoded with MarshalBinary. It returns an
+// error, and leaves the trace unchanged, when the data is truncated or corrupt.
+func (t *Trace) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	version, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != encodingVersion {
+		return fmt.Errorf("unsupported trace encoding version %d", version)
+	}
+
+	numVariables, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	decoded := Trace{}
+	for i := uint64(0); i < numVariables; i++ {
+		name, err := readString(r)
+		if err != nil {
+			return err
+		}
+		numSamples, err := binary.ReadUvarint(r)
+		if err != nil {
+			return err
+		}
+		// Check the length against the data before allocating, since the
+		// input may be corrupt or malicious.
+		if numSamples > uint64(r.Len()/8) {
+			return io.ErrUnexpectedEOF
+		}
+		samples := make([]float64, numSamples)
+		for j := range samples {
+			var bits uint64
+			if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+				return err
+			}
+			samples[j] = math.Float64frombits(bits)
+		}
+		decoded[name] = samples
+	}
+	if r.Len() > 0 {
+		return fmt.Errorf("%d unexpected bytes after the trace", r.Len())
+	}
+	*t = decoded
+
+	return nil
+}
+
+func writeUint(b *bytes.Buffer, n int) {
+	var buf [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(buf[:], uint64(n))
+	b.Write(buf[:size])
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	traces := []Trace{
+		{},
+		{"a": {}},
+		{"mu": {1, -2.5, math.Inf(1), 0}, "sigma": {0.1, 0.2, 0.3, 0.4}, "é": {math.MaxFloat64}},
+	}
+	for _, original := range traces {
+		data, err := original.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Trace
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("decoding %v: %v", original, err)
+		}
+		if !reflect.DeepEqual(decoded, original) {
+			t.Errorf("decoded %v, expected %v", decoded, original)
+		}
+	}
+}
+
+func TestBinaryCorruptInput(t *testing.T) {
+	data, err := Trace{"mu": {1, 2, 3}, "sigma": {4, 5, 6}}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := map[string][]byte{
+		"empty":       {},
+		"version":     {encodingVersion + 1, 0},
+		"huge name":   {encodingVersion, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+		"huge length": {encodingVersion, 1, 1, 'a', 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+		"trailing":    append(append([]byte(nil), data...), 0),
+	}
+	for n := 1; n < len(data); n++ {
+		inputs[fmt.Sprintf("truncated at %d", n)] = data[:n]
+	}
+
+	for name, input := range inputs {
+		decoded := Trace{"kept": {1}}
+		if err := decoded.UnmarshalBinary(input); err == nil {
+			t.Errorf("%s: decoding %v returned no error", name, input)
+		}
+		if len(decoded) != 1 || decoded["kept"] == nil {
+			t.Errorf("%s: the trace was modified by a failed decoding", name)
+		}
+	}
+}
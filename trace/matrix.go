@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Matrix returns the samples of the given variables as a matrix with one row
+// per draw and one column per variable, in the order of the names. All the
+// variables are included, in alphabetical order, when no name is given.
+func (t Trace) Matrix(names ...string) *mat.Dense {
+	if len(names) == 0 {
+		names = t.Names()
+	}
+	numDraws := t.numDraws(names)
+
+	m := mat.NewDense(numDraws, len(names), nil)
+	for j, name := range names {
+		m.SetCol(j, t[name])
+	}
+	return m
+}
+
+// Stack returns the samples of several chains as a single matrix in which
+// the draws of each chain follow the draws of the previous one. Use
+// Trace.Matrix on each chain to keep them separate.
+func Stack(chains []Trace, names ...string) *mat.Dense {
+	if len(chains) == 0 {
+		log.Panicf("needed at least one chain")
+	}
+	if len(names) == 0 {
+		names = chains[0].Names()
+	}
+
+	var numDraws int
+	for _, chain := range chains {
+		numDraws += chain.numDraws(names)
+	}
+	m := mat.NewDense(numDraws, len(names), nil)
+	row := 0
+	for _, chain := range chains {
+		n := chain.numDraws(names)
+		m.Slice(row, row+n, 0, len(names)).(*mat.Dense).Copy(chain.Matrix(names...))
+		row += n
+	}
+	return m
+}
+
+// numDraws returns the number of draws of the variables, and panics if some
+// are missing or if their number of draws differ.
+func (t Trace) numDraws(names []string) int {
+	numDraws := -1
+	for _, name := range names {
+		samples, ok := t[name]
+		if !ok {
+			log.Panicf("The trace is missing variable %s", name)
+		}
+		if numDraws == -1 {
+			numDraws = len(samples)
+		} else if len(samples) != numDraws {
+			log.Panicf("variable %s has %d samples, expected %d", name, len(samples), numDraws)
+		}
+	}
+	if numDraws < 1 {
+		log.Panicf("the trace is empty")
+	}
+	return numDraws
+}
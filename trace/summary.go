@@ -0,0 +1,111 @@
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// A Summary contains point estimates of the posterior distribution of a
+// variable.
+type Summary struct {
+	Name   string
+	Mean   float64
+	StdDev float64
+	Median float64
+	Mode   float64
+}
+
+// Summary returns the summary of every variable in the trace, ordered by
+// name.
+func (t Trace) Summary() []Summary {
+	var summaries []Summary
+	for _, name := range t.Names() {
+		summaries = append(summaries, Summarize(name, t[name]))
+	}
+	return summaries
+}
+
+// Summarize computes the summary of the samples of a single variable. The
+// estimates are NaN when there are no samples.
+func Summarize(name string, samples []float64) Summary {
+	if len(samples) == 0 {
+		nan := math.NaN()
+		return Summary{Name: name, Mean: nan, StdDev: nan, Median: nan, Mode: nan}
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	mean, std := stat.MeanStdDev(sorted, nil)
+	return Summary{
+		Name:   name,
+		Mean:   mean,
+		StdDev: std,
+		Median: stat.Quantile(0.5, stat.Empirical, sorted, nil),
+		Mode:   halfSampleMode(sorted),
+	}
+}
+
+// Mode estimates the mode of the distribution the samples were drawn from.
+func Mode(samples []float64) float64 {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	return halfSampleMode(sorted)
+}
+
+// halfSampleMode computes the half-sample mode of sorted samples. It
+// repeatedly keeps the half of the samples that lie in the shortest
+// interval until only a few samples remain:
+//
+// "On the computation of the half-sample mode" (Bickel & Frühwirth 2006)
+// https://doi.org/10.1016/j.csda.2005.07.011
+//
+// Unlike kernel density estimates, it does not depend on a bandwidth and is
+// robust to outliers.
+func halfSampleMode(sorted []float64) float64 {
+	x := sorted
+	for len(x) > 3 {
+		half := (len(x) + 1) / 2
+		start, width := 0, x[half-1]-x[0]
+		for i := 1; i+half <= len(x); i++ {
+			if w := x[i+half-1] - x[i]; w < width {
+				start, width = i, w
+			}
+		}
+		x = x[start : start+half]
+	}
+
+	switch len(x) {
+	case 3:
+		left, right := x[1]-x[0], x[2]-x[1]
+		if left < right {
+			return (x[0] + x[1]) / 2
+		}
+		if left > right {
+			return (x[1] + x[2]) / 2
+		}
+		return x[1]
+	case 2:
+		return (x[0] + x[1]) / 2
+	case 1:
+		return x[0]
+	}
+	return 0
+}
+
+// HTML renders the summary of the trace as an HTML table. Notebooks such as
+// gophernotes use it to display traces.
+func (t Trace) HTML() string {
+	var b bytes.Buffer
+	b.WriteString("<table>\n<tr><th>variable</th><th>samples</th><th>mean</th><th>sd</th><th>median</th><th>mode</th></tr>\n")
+	for _, s := range t.Summary() {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%d</td><td>%.3g</td><td>%.3g</td><td>%.3g</td><td>%.3g</td></tr>\n",
+			html.EscapeString(s.Name), len(t[s.Name]), s.Mean, s.StdDev, s.Median, s.Mode)
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
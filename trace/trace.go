@@ -0,0 +1,67 @@
+// Package trace contains the samples drawn from a model's posterior
+// distribution and the tools to summarize them.
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+)
+
+// A Trace links the name of each variable to the values that were sampled
+// for this variable.
+type Trace map[string][]float64
+
+// Names returns the names of the variables in the trace in alphabetical
+// order.
+func (t Trace) Names() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String describes the variables contained in the trace and the number of
+// samples drawn for each of them.
+func (t Trace) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Trace with %d variables", len(t))
+	for _, name := range t.Names() {
+		fmt.Fprintf(&b, "\n  %s: %d samples", name, len(t[name]))
+	}
+	return b.String()
+}
+
+// Discard returns a trace without the first n samples of every variable,
+// typically the burn-in phase of the chain.
+func (t Trace) Discard(n int) Trace {
+	discarded := Trace{}
+	for name, samples := range t {
+		if n > len(samples) {
+			discarded[name] = []float64{}
+			continue
+		}
+		discarded[name] = samples[n:]
+	}
+	return discarded
+}
+
+// Thin returns a trace that only contains every k-th sample of every
+// variable, starting with the first one.
+func (t Trace) Thin(k int) Trace {
+	if k < 1 {
+		log.Panicf("the thinning interval must be at least 1, got %d", k)
+	}
+	thinned := Trace{}
+	for name, samples := range t {
+		kept := make([]float64, 0, (len(samples)+k-1)/k)
+		for i := 0; i < len(samples); i += k {
+			kept = append(kept, samples[i])
+		}
+		thinned[name] = kept
+	}
+	return thinned
+}